@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	configs "github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+	providers "github.com/DeveloperPaul123/agenda/internal/providers"
+)
+
+// defaultEventLength is the length of events created without --for.
+const defaultEventLength = 30 * time.Minute
+
+// newAddCommand creates the command that creates an event.
+func newAddCommand() *cobra.Command {
+	addCmd := &cobra.Command{
+		Use:   "add TITLE",
+		Short: "Create an event, e.g. agenda add \"Review\" --at 14:00 --for 30m",
+		Args:  cobra.ExactArgs(1),
+		Run:   runAdd,
+	}
+	addCmd.Flags().String("at", "", "Start time of the event, HH:MM on the day given with --date")
+	addCmd.Flags().Duration("for", defaultEventLength, "Length of the event, e.g. 30m or 1h30m")
+	addCmd.Flags().String("calendar", "", "Calendar to create the event in (default is the default_calendar of the provider)")
+	addCmd.Flags().Bool("dry-run", false, "Print the request instead of creating the event")
+	addCmd.MarkFlagRequired("at")
+	return addCmd
+}
+
+// runAdd creates an event through the configured provider.
+func runAdd(cmd *cobra.Command, args []string) {
+	config := loadConfig(cmd)
+	at, _ := cmd.Flags().GetString("at")
+	length, _ := cmd.Flags().GetDuration("for")
+	calendar, _ := cmd.Flags().GetString("calendar")
+
+	now := currentTime(config)
+	day, _ := parseDateFlag(cmd, "date", now)
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		log.Fatalf("Invalid start time %q, expected HH:MM", at)
+	}
+	if length <= 0 {
+		log.Fatalf("Invalid length %s, it must be positive", length)
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location())
+	event := models.CalendarEvent{
+		Title:        args[0],
+		StartTime:    start,
+		EndTime:      start.Add(length),
+		CalendarName: calendar,
+	}
+
+	created, err := writableProvider(cmd, config).CreateEvent(event)
+	if errors.Is(err, providers.ErrDryRun) {
+		fmt.Println("Dry run, the event was not created.")
+		return
+	}
+	if err != nil {
+		log.Fatalf("Failed to create event: %v", err)
+	}
+
+	formatter := newFormatter(config)
+	fmt.Printf("Created %s on %s %s in %s\n", created.Title, formatter.FormatDate(created.StartTime), formatter.FormatTimeRange(created), created.CalendarName)
+}
+
+// writableProvider creates the configured provider and checks that it can change events.
+// In dry-run mode the provider prints the requests that would change data instead of sending them.
+func writableProvider(cmd *cobra.Command, config configs.Config) providers.WritableProvider {
+	calProvider, err := providers.NewProviderFactory(config, providerOptions(cmd)).CreateProvider(config.Provider)
+	if err != nil {
+		log.Fatalf("Failed to create provider: %v", err)
+	}
+	writable, ok := calProvider.(providers.WritableProvider)
+	if !ok {
+		log.Fatalf("Provider %s is read-only, events cannot be changed through it", config.Provider)
+	}
+	return writable
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	configs "github.com/DeveloperPaul123/agenda/internal/configs"
+	oauth "github.com/DeveloperPaul123/agenda/internal/oauth"
+)
+
+// authTimeout is how long the auth command waits for the user to authorize in the browser.
+const authTimeout = 5 * time.Minute
+
+// newAuthCommand creates the command that authorizes agenda to access an OAuth provider.
+func newAuthCommand() *cobra.Command {
+	authCmd := &cobra.Command{
+		Use:   "auth [provider]",
+		Short: "Authorize agenda to access the calendars of an OAuth provider",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   runAuth,
+	}
+	authCmd.Flags().Bool("no-browser", false, "Only print the authorization link instead of opening it")
+	return authCmd
+}
+
+// runAuth runs the OAuth authorization-code flow for the provider and stores the token.
+func runAuth(cmd *cobra.Command, args []string) {
+	config := loadConfig(cmd)
+	noBrowser, _ := cmd.Flags().GetBool("no-browser")
+
+	name := config.Provider
+	if len(args) > 0 {
+		name = args[0]
+	}
+	providerConfig, exists := config.Providers[name]
+	if !exists {
+		log.Fatalf("Provider %s not found in configuration", name)
+	}
+	if providerConfig.OAuth == nil {
+		log.Fatalf("Provider %s is not configured for OAuth, add an oauth section to its configuration", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authTimeout)
+	defer cancel()
+
+	client := oauth.NewClient(*providerConfig.OAuth)
+	token, err := client.Authorize(ctx, func(authURL string) error {
+		fmt.Printf("Open this link to authorize agenda:\n\n  %s\n\n", authURL)
+		if !noBrowser {
+			if err := openURL(authURL); err != nil {
+				fmt.Printf("Failed to open the browser: %v\n", err)
+			}
+		}
+		fmt.Println("Waiting for authorization...")
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to authorize %s: %v", name, err)
+	}
+
+	if err := oauth.NewStore(configs.TokenDir()).Save(name, token); err != nil {
+		log.Fatalf("Failed to store token: %v", err)
+	}
+	fmt.Printf("Authorized %s, the token is stored in %s\n", name, configs.TokenDir())
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	configs "github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+	providers "github.com/DeveloperPaul123/agenda/internal/providers"
+)
+
+// newMoveCommand creates the command that moves an event to another time.
+func newMoveCommand() *cobra.Command {
+	moveCmd := &cobra.Command{
+		Use:   "move EVENT",
+		Short: "Move an event to another time, e.g. agenda move \"Review\" --to 15:00",
+		Args:  cobra.ExactArgs(1),
+		Run:   runMove,
+	}
+	moveCmd.Flags().String("to", "", "New start time of the event, HH:MM")
+	moveCmd.Flags().String("on", "", "Day to move the event to, accepts the same formats as --date (default is the day of the event)")
+	moveCmd.MarkFlagRequired("to")
+	addChangeFlags(moveCmd)
+	return moveCmd
+}
+
+// newRenameCommand creates the command that changes the title of an event.
+func newRenameCommand() *cobra.Command {
+	renameCmd := &cobra.Command{
+		Use:   "rename EVENT TITLE",
+		Short: "Change the title of an event",
+		Args:  cobra.ExactArgs(2),
+		Run:   runRename,
+	}
+	addChangeFlags(renameCmd)
+	return renameCmd
+}
+
+// newDeleteCommand creates the command that deletes an event.
+func newDeleteCommand() *cobra.Command {
+	deleteCmd := &cobra.Command{
+		Use:   "delete EVENT",
+		Short: "Delete an event",
+		Args:  cobra.ExactArgs(1),
+		Run:   runDelete,
+	}
+	addChangeFlags(deleteCmd)
+	return deleteCmd
+}
+
+// addChangeFlags adds the flags shared by the commands that change events.
+func addChangeFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	cmd.Flags().Bool("dry-run", false, "Print the request instead of changing the event")
+}
+
+// runMove moves an event to another start time, keeping its length.
+func runMove(cmd *cobra.Command, args []string) {
+	config := loadConfig(cmd)
+	at, _ := cmd.Flags().GetString("to")
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		log.Fatalf("Invalid start time %q, expected HH:MM", at)
+	}
+
+	provider, event := findEvent(cmd, config, args[0])
+	if event.AllDay {
+		log.Fatalf("%s lasts all day and cannot be moved to a time", event.Title)
+	}
+
+	day := event.StartTime
+	if cmd.Flags().Changed("on") {
+		day, _ = parseDateFlag(cmd, "on", currentTime(config))
+	}
+	moved := event
+	moved.StartTime = time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location())
+	moved.EndTime = moved.StartTime.Add(event.EndTime.Sub(event.StartTime))
+
+	formatter := newFormatter(config)
+	question := fmt.Sprintf("Move %s from %s to %s %s?", describeEvent(formatter, event), formatter.FormatTimeRange(event),
+		formatter.FormatDate(moved.StartTime), formatter.FormatTimeRange(moved))
+	if !confirmChange(cmd, question) {
+		return
+	}
+
+	_, err = provider.UpdateEvent(event, moved)
+	if reportChange(err, "move") {
+		fmt.Printf("Moved %s to %s %s\n", moved.Title, formatter.FormatDate(moved.StartTime), formatter.FormatTimeRange(moved))
+	}
+}
+
+// runRename changes the title of an event.
+func runRename(cmd *cobra.Command, args []string) {
+	config := loadConfig(cmd)
+	title := strings.TrimSpace(args[1])
+	if title == "" {
+		log.Fatalf("The new title must not be empty")
+	}
+
+	provider, event := findEvent(cmd, config, args[0])
+	renamed := event
+	renamed.Title = title
+
+	formatter := newFormatter(config)
+	if !confirmChange(cmd, fmt.Sprintf("Rename %s to %s?", describeEvent(formatter, event), title)) {
+		return
+	}
+
+	_, err := provider.UpdateEvent(event, renamed)
+	if reportChange(err, "rename") {
+		fmt.Printf("Renamed %s to %s\n", event.Title, title)
+	}
+}
+
+// runDelete deletes an event.
+func runDelete(cmd *cobra.Command, args []string) {
+	config := loadConfig(cmd)
+	provider, event := findEvent(cmd, config, args[0])
+
+	formatter := newFormatter(config)
+	if !confirmChange(cmd, fmt.Sprintf("Delete %s?", describeEvent(formatter, event))) {
+		return
+	}
+
+	err := provider.DeleteEvent(event)
+	if reportChange(err, "delete") {
+		fmt.Printf("Deleted %s\n", event.Title)
+	}
+}
+
+// findEvent returns the event on the day given with --date that the target names, either by its ID
+// or by its title, and the provider it belongs to. The events of the configured provider and the
+// providers merged with it that can change events are searched.
+func findEvent(cmd *cobra.Command, config configs.Config, target string) (providers.WritableProvider, models.CalendarEvent) {
+	names, calProviders := createProviders(cmd, config)
+	start, end := parseDateFlag(cmd, "date", currentTime(config))
+
+	var events []models.CalendarEvent
+	writable := make(map[string]providers.WritableProvider)
+	for i, calProvider := range calProviders {
+		provider, ok := calProvider.(providers.WritableProvider)
+		if !ok {
+			continue
+		}
+		writable[names[i]] = provider
+
+		providerEvents, err := provider.GetEvents(start, end)
+		if err != nil {
+			log.Fatalf("Failed to get events from %s: %v", names[i], err)
+		}
+		for _, event := range providerEvents {
+			event.StartTime = event.StartTime.In(start.Location())
+			event.EndTime = event.EndTime.In(start.Location())
+			event.Provider = names[i]
+			events = append(events, event)
+		}
+	}
+	if len(writable) == 0 {
+		log.Fatalf("None of the providers %s can change events", strings.Join(names, ", "))
+	}
+
+	event := events[pickEvent(config, events, target)]
+	return writable[event.Provider], event
+}
+
+// pickEvent returns the index of the only event that the target names, see matchEvents. It exits if
+// there is no such event or if several events match, listing their IDs.
+func pickEvent(config configs.Config, events []models.CalendarEvent, target string) int {
+	matches := matchEvents(events, target)
+	switch len(matches) {
+	case 0:
+		log.Fatalf("No event matching %q found, use --date for events on other days", target)
+	case 1:
+		return matches[0]
+	}
+
+	formatter := newFormatter(config)
+	var candidates []string
+	for _, i := range matches {
+		event := events[i]
+		candidates = append(candidates, fmt.Sprintf("  %s %s [%s]", formatter.FormatTimeRange(event), event.Title, event.ID))
+	}
+	log.Fatalf("%d events match %q, use the ID of one of them:\n%s", len(matches), target, strings.Join(candidates, "\n"))
+	return -1
+}
+
+// matchEvents returns the index of the event with the target as ID, otherwise the indexes of the events
+// with the target as title, or if there are none of the events whose title contains it. Titles are
+// compared ignoring case.
+func matchEvents(events []models.CalendarEvent, target string) []int {
+	var titled, containing []int
+	for i, event := range events {
+		switch {
+		case event.ID != "" && event.ID == target:
+			return []int{i}
+		case strings.EqualFold(event.Title, target):
+			titled = append(titled, i)
+		case strings.Contains(strings.ToLower(event.Title), strings.ToLower(target)):
+			containing = append(containing, i)
+		}
+	}
+	if len(titled) > 0 {
+		return titled
+	}
+	return containing
+}
+
+// describeEvent names an event with its day and calendar for confirmation prompts.
+func describeEvent(formatter *EventFormatter, event models.CalendarEvent) string {
+	description := fmt.Sprintf("%s on %s", event.Title, formatter.FormatDate(event.StartTime))
+	if event.CalendarName != "" {
+		description += fmt.Sprintf(" in %s", event.CalendarName)
+	}
+	return description
+}
+
+// confirmChange asks the question and reports whether it was answered with yes. It does not ask
+// with --yes or --dry-run.
+func confirmChange(cmd *cobra.Command, question string) bool {
+	yes, _ := cmd.Flags().GetBool("yes")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if yes || dryRun {
+		return true
+	}
+
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	fmt.Println("Nothing was changed.")
+	return false
+}
+
+// reportChange handles the error of a change, it reports whether the change was made.
+func reportChange(err error, action string) bool {
+	if errors.Is(err, providers.ErrDryRun) {
+		fmt.Println("Dry run, the event was not changed.")
+		return false
+	}
+	if err != nil {
+		log.Fatalf("Failed to %s event: %v", action, err)
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	configs "github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// editEvents are two recurring standups, a review and a review of the design on the same day.
+func editEvents() []models.CalendarEvent {
+	morning := testEvent("Standup", "09:00", "09:15")
+	morning.ID = "standup-1"
+	afternoon := testEvent("standup", "16:00", "16:15")
+	afternoon.ID = "standup-2"
+	return []models.CalendarEvent{
+		morning,
+		testEvent("Review", "11:00", "12:00"),
+		testEvent("Design review", "14:00", "15:00"),
+		afternoon,
+	}
+}
+
+func TestMatchEvents(t *testing.T) {
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		// IDs win over titles
+		{"standup-2", []string{"standup-2"}},
+		// Equal titles are ambiguous, regardless of case
+		{"Standup", []string{"standup-1", "standup-2"}},
+		{"STANDUP", []string{"standup-1", "standup-2"}},
+		// An equal title is preferred over titles containing the target
+		{"review", []string{"review"}},
+		{"design", []string{"design review"}},
+		{"stand", []string{"standup-1", "standup-2"}},
+		{"retro", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			events := editEvents()
+			var got []string
+			for _, i := range matchEvents(events, tt.target) {
+				got = append(got, events[i].ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("matchEvents(%q) = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickEvent(t *testing.T) {
+	if i := pickEvent(configs.DefaultConfig(), editEvents(), "design"); i != 2 {
+		t.Errorf("pickEvent() = %d, want 2 for the design review", i)
+	}
+}
+
+func TestPickEventAmbiguous(t *testing.T) {
+	// pickEvent exits if the target is ambiguous, so it runs in a child process
+	if target := os.Getenv("AGENDA_TEST_PICK_EVENT"); target != "" {
+		pickEvent(configs.DefaultConfig(), editEvents(), target)
+		return
+	}
+
+	for _, target := range []string{"standup", "retro"} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestPickEventAmbiguous$")
+		cmd.Env = append(os.Environ(), "AGENDA_TEST_PICK_EVENT="+target)
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			t.Errorf("pickEvent(%q) did not exit", target)
+			continue
+		}
+		if target == "standup" && (!strings.Contains(string(output), "2 events match") ||
+			!strings.Contains(string(output), "[standup-1]") || !strings.Contains(string(output), "[standup-2]")) {
+			t.Errorf("pickEvent(%q) output does not list the matching events:\n%s", target, output)
+		}
+		if target == "retro" && !strings.Contains(string(output), "No event matching") {
+			t.Errorf("pickEvent(%q) output = %s", target, output)
+		}
+	}
+}
+
+func TestConfirmChange(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags []string
+		input string
+		want  bool
+	}{
+		{"yes", nil, "y\n", true},
+		{"yes in words", nil, " Yes \n", true},
+		{"no", nil, "n\n", false},
+		{"empty answer", nil, "\n", false},
+		{"no input", nil, "", false},
+		{"--yes", []string{"--yes"}, "", true},
+		{"--dry-run", []string{"--dry-run"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addChangeFlags(cmd)
+			if err := cmd.ParseFlags(tt.flags); err != nil {
+				t.Fatal(err)
+			}
+
+			reader, writer, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			writer.WriteString(tt.input)
+			writer.Close()
+			stdin := os.Stdin
+			os.Stdin = reader
+			defer func() {
+				os.Stdin = stdin
+				reader.Close()
+			}()
+
+			if got := confirmChange(cmd, "Delete Review?"); got != tt.want {
+				t.Errorf("confirmChange() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
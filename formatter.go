@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	analysis "github.com/DeveloperPaul123/agenda/internal/analysis"
+	description "github.com/DeveloperPaul123/agenda/internal/description"
+	locale "github.com/DeveloperPaul123/agenda/internal/locale"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// EventFormatter handles formatting events for output to the console.
+type EventFormatter struct {
+	// timeFormat and dateFormat are Go layouts, named formats are resolved when they are set.
+	timeFormat    string
+	dateFormat    string
+	locale        *locale.Locale
+	eventTemplate *template.Template
+	descriptions  *description.Normalizer
+	// extraLocations are the additional timezones shown for each event.
+	extraLocations []*time.Location
+	// locations caches the timezones loaded for the Times template method.
+	locations map[string]*time.Location
+}
+
+// eventData is the data made available to event templates.
+type eventData struct {
+	models.CalendarEvent
+	StartTimeFormatted string
+	EndTimeFormatted   string
+	Duration           string
+	// DescriptionMarkdown is the description converted to markdown without conferencing boilerplate.
+	DescriptionMarkdown string
+	// DescriptionShort is DescriptionMarkdown on a single line, truncated to the configured length.
+	DescriptionShort string
+	// Conflicts holds the titles of the events this one overlaps with.
+	Conflicts []string
+	// ExtraTimes holds the time range of the event in each of the configured extra timezones.
+	ExtraTimes []string
+
+	formatter *EventFormatter
+}
+
+// Times returns the time range of the event in the timezone with the given IANA name,
+// e.g. {{.Times "America/Los_Angeles"}} in a template.
+func (d eventData) Times(timezone string) (string, error) {
+	loc, err := d.formatter.loadLocation(timezone)
+	if err != nil {
+		return "", err
+	}
+	return d.formatter.formatTimeRangeIn(d.CalendarEvent, loc), nil
+}
+
+// templateFuncs returns the helper functions available to templates.
+func (f *EventFormatter) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"join":       strings.Join,
+		"duration":   formatDuration,
+		"formatTime": f.formatTime,
+		"formatDate": f.FormatDate,
+		"formatDateAs": func(format string, t time.Time) string {
+			return f.locale.Format(t, f.locale.DateLayout(format))
+		},
+	}
+}
+
+// formatDuration formats a duration rounded to minutes without trailing zero units, e.g. "1h30m" or "2h".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := d / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
+
+// NewEventFormatter creates a new EventFormatter with the given time format and event template string.
+// The time format is either a Go layout or one of the named formats 24h, 12h or iso.
+func NewEventFormatter(timeFormat, eventTemplateStr string) (*EventFormatter, error) {
+	descriptions, err := description.NewNormalizer(nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	defaultLocale, err := locale.Get(locale.DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &EventFormatter{
+		timeFormat:   locale.TimeLayout(timeFormat),
+		dateFormat:   defaultLocale.DateLayout(""),
+		locale:       defaultLocale,
+		descriptions: descriptions,
+		locations:    make(map[string]*time.Location),
+	}
+	f.eventTemplate, err = template.New("event").Funcs(f.templateFuncs()).Parse(eventTemplateStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse event template: %w", err)
+	}
+	return f, nil
+}
+
+// SetLocale sets the locale used for day and month names and the date format,
+// which is either a Go layout or one of the named formats short, long or iso.
+func (f *EventFormatter) SetLocale(l *locale.Locale, dateFormat string) {
+	f.locale = l
+	f.dateFormat = l.DateLayout(dateFormat)
+}
+
+// FormatDate formats a date using the configured date format and locale, e.g. "Mon 2025-03-10".
+func (f *EventFormatter) FormatDate(t time.Time) string {
+	return f.locale.Format(t, f.dateFormat)
+}
+
+// formatTime formats a time using the configured time format and locale.
+func (f *EventFormatter) formatTime(t time.Time) string {
+	return f.locale.Format(t, f.timeFormat)
+}
+
+// SetExtraTimezones sets the timezones, by IANA name, the event times are additionally shown in.
+func (f *EventFormatter) SetExtraTimezones(timezones []string) error {
+	f.extraLocations = nil
+	for _, timezone := range timezones {
+		loc, err := f.loadLocation(timezone)
+		if err != nil {
+			return err
+		}
+		f.extraLocations = append(f.extraLocations, loc)
+	}
+	return nil
+}
+
+// loadLocation loads the timezone with the given IANA name.
+func (f *EventFormatter) loadLocation(timezone string) (*time.Location, error) {
+	if loc, exists := f.locations[timezone]; exists {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	f.locations[timezone] = loc
+	return loc, nil
+}
+
+// SetDescriptionNormalizer sets the normalizer used for the markdown and short description fields.
+func (f *EventFormatter) SetDescriptionNormalizer(normalizer *description.Normalizer) {
+	f.descriptions = normalizer
+}
+
+// FormatEvent formats a CalendarEvent using the configured template and time format.
+// The conflicts are the titles of other events that overlap with this one.
+func (f *EventFormatter) FormatEvent(event models.CalendarEvent, conflicts []string) (string, error) {
+	data := eventData{
+		CalendarEvent:       event,
+		StartTimeFormatted:  f.formatTime(event.StartTime),
+		EndTimeFormatted:    f.formatTime(event.EndTime),
+		Duration:            event.EndTime.Sub(event.StartTime).String(),
+		DescriptionMarkdown: f.descriptions.Normalize(event.Description),
+		DescriptionShort:    f.descriptions.Short(event.Description),
+		Conflicts:           conflicts,
+		ExtraTimes:          f.ExtraTimes(event),
+		formatter:           f,
+	}
+
+	var result strings.Builder
+	if err := f.eventTemplate.Execute(&result, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return result.String(), nil
+}
+
+// FormatSummary formats the meeting statistics using the given template string.
+func (f *EventFormatter) FormatSummary(summaryTemplateStr string, summary analysis.Summary) (string, error) {
+	return f.execute("summary", summaryTemplateStr, summary)
+}
+
+// FormatGroupHeading formats the heading of a group of events using the given template string.
+func (f *EventFormatter) FormatGroupHeading(headingTemplateStr string, group eventGroup) (string, error) {
+	return f.execute("group heading", headingTemplateStr, group)
+}
+
+// FormatDayHeading formats the heading of a day of the agenda using the given template string.
+func (f *EventFormatter) FormatDayHeading(headingTemplateStr string, day agendaDay) (string, error) {
+	return f.execute("day heading", headingTemplateStr, day)
+}
+
+// FormatTask formats a task using the given template string.
+func (f *EventFormatter) FormatTask(taskTemplateStr string, task models.Task) (string, error) {
+	return f.execute("task", taskTemplateStr, task)
+}
+
+// FormatTaskHeading formats the heading of the tasks using the given template string.
+func (f *EventFormatter) FormatTaskHeading(headingTemplateStr string, tasks []models.Task) (string, error) {
+	return f.execute("task heading", headingTemplateStr, tasks)
+}
+
+// execute parses the template string and executes it with the given data.
+func (f *EventFormatter) execute(name, templateStr string, data any) (string, error) {
+	tmpl, err := template.New(name).Funcs(f.templateFuncs()).Parse(templateStr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return result.String(), nil
+}
+
+// FormatTimeRange formats the start and end time of an event using the configured time format.
+func (f *EventFormatter) FormatTimeRange(event models.CalendarEvent) string {
+	return fmt.Sprintf("%s-%s", f.formatTime(event.StartTime), f.formatTime(event.EndTime))
+}
+
+// ExtraTimes formats the time range of an event in each of the extra timezones, e.g. "00:00-00:30 PST".
+func (f *EventFormatter) ExtraTimes(event models.CalendarEvent) []string {
+	times := make([]string, 0, len(f.extraLocations))
+	for _, loc := range f.extraLocations {
+		times = append(times, f.formatTimeRangeIn(event, loc))
+	}
+	return times
+}
+
+// formatTimeRangeIn formats the time range of an event in the given timezone followed by the zone abbreviation.
+func (f *EventFormatter) formatTimeRangeIn(event models.CalendarEvent, loc *time.Location) string {
+	start, end := event.StartTime.In(loc), event.EndTime.In(loc)
+	return fmt.Sprintf("%s-%s %s", f.formatTime(start), f.formatTime(end), start.Format("MST"))
+}
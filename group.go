@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// Supported values for the group_by option.
+const (
+	groupByNone      = ""
+	groupByCalendar  = "calendar"
+	groupByAccount   = "account"
+	groupByProvider  = "provider"
+	groupByTimeOfDay = "morning-afternoon"
+)
+
+// Names of the time of day groups, in the order they are printed.
+const (
+	morningGroup   = "Morning"
+	afternoonGroup = "Afternoon"
+	eveningGroup   = "Evening"
+)
+
+// eventGroup is a section of the agenda with its own heading.
+type eventGroup struct {
+	Name string
+	// Count is the number of events in the group.
+	Count int
+	// indices of the group's events in the sorted event list.
+	indices []int
+}
+
+// groupEvents splits the sorted events into groups according to the group_by option.
+// The events keep their order within each group. Without grouping a single unnamed group is returned.
+func groupEvents(events []models.CalendarEvent, groupBy string) ([]eventGroup, error) {
+	var keyFunc func(event models.CalendarEvent) string
+	// nameFunc names the group of an event, groups are named by their key if it is not set
+	var nameFunc func(event models.CalendarEvent) string
+	switch groupBy {
+	case groupByNone:
+		keyFunc = func(event models.CalendarEvent) string { return "" }
+	case groupByCalendar:
+		keyFunc = func(event models.CalendarEvent) string { return event.CalendarName }
+	case groupByAccount:
+		keyFunc = func(event models.CalendarEvent) string { return event.AccountID }
+		nameFunc = accountName
+	case groupByProvider:
+		keyFunc = func(event models.CalendarEvent) string { return event.Provider }
+	case groupByTimeOfDay:
+		keyFunc = timeOfDay
+	default:
+		return nil, fmt.Errorf("unsupported group by %q, use one of: %s, %s, %s, %s",
+			groupBy, groupByCalendar, groupByAccount, groupByProvider, groupByTimeOfDay)
+	}
+
+	groupsByKey := make(map[string]*eventGroup)
+	var groups []*eventGroup
+	for i, event := range events {
+		key := keyFunc(event)
+		group, exists := groupsByKey[key]
+		if !exists {
+			group = &eventGroup{Name: key}
+			if nameFunc != nil {
+				group.Name = nameFunc(event)
+			}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+		group.indices = append(group.indices, i)
+		group.Count++
+	}
+
+	// Time of day groups are already in chronological order
+	if groupBy != groupByTimeOfDay {
+		sort.SliceStable(groups, func(i, j int) bool {
+			return groups[i].Name < groups[j].Name
+		})
+	}
+
+	result := make([]eventGroup, 0, len(groups))
+	for _, group := range groups {
+		if group.Name == "" && groupBy != groupByNone {
+			group.Name = "Other"
+		}
+		result = append(result, *group)
+	}
+	return result, nil
+}
+
+// accountName returns the name of the account of the event, or its ID if the provider does not know the name.
+func accountName(event models.CalendarEvent) string {
+	if event.AccountName != "" {
+		return event.AccountName
+	}
+	return event.AccountID
+}
+
+// timeOfDay returns the name of the part of the day the event starts in.
+func timeOfDay(event models.CalendarEvent) string {
+	switch hour := event.StartTime.Hour(); {
+	case hour < 12:
+		return morningGroup
+	case hour < 17:
+		return afternoonGroup
+	default:
+		return eveningGroup
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// testEvent creates an event on March 10, 2025 in UTC, the times are given as HH:MM.
+func testEvent(title, start, end string) models.CalendarEvent {
+	return models.CalendarEvent{ID: strings.ToLower(title), Title: title, StartTime: testTime(start), EndTime: testTime(end)}
+}
+
+// testTime returns the time of day on March 10, 2025 in UTC.
+func testTime(clock string) time.Time {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		panic(err)
+	}
+	return time.Date(2025, 3, 10, parsed.Hour(), parsed.Minute(), 0, 0, time.UTC)
+}
+
+// groupNames formats the groups as "name: title, title; name: title".
+func groupNames(events []models.CalendarEvent, groups []eventGroup) string {
+	var formatted []string
+	for _, group := range groups {
+		var titles []string
+		for _, index := range group.indices {
+			titles = append(titles, events[index].Title)
+		}
+		if len(titles) != group.Count {
+			titles = append(titles, "count mismatch")
+		}
+		formatted = append(formatted, group.Name+": "+strings.Join(titles, ", "))
+	}
+	return strings.Join(formatted, "; ")
+}
+
+func TestGroupEvents(t *testing.T) {
+	standup := testEvent("Standup", "09:00", "09:15")
+	standup.CalendarName, standup.AccountID, standup.AccountName, standup.Provider = "Work", "acc-1", "me@work.com", "morgen"
+	lunch := testEvent("Lunch", "11:59", "12:30")
+	lunch.CalendarName, lunch.AccountID, lunch.Provider = "Personal", "acc-2", "morgen"
+	review := testEvent("Review", "12:00", "13:00")
+	review.CalendarName, review.AccountID, review.AccountName, review.Provider = "Work", "acc-1", "me@work.com", "google"
+	oneOnOne := testEvent("1:1", "16:59", "17:30")
+	oneOnOne.Provider = "manual"
+	dinner := testEvent("Dinner", "17:00", "19:00")
+	dinner.CalendarName, dinner.Provider = "Personal", "manual"
+	events := []models.CalendarEvent{standup, lunch, review, oneOnOne, dinner}
+
+	tests := []struct {
+		groupBy string
+		want    string
+	}{
+		{groupByNone, ": Standup, Lunch, Review, 1:1, Dinner"},
+		{groupByCalendar, "Other: 1:1; Personal: Lunch, Dinner; Work: Standup, Review"},
+		// Accounts are named by their name, or their ID if the provider does not know it
+		{groupByAccount, "Other: 1:1, Dinner; acc-2: Lunch; me@work.com: Standup, Review"},
+		{groupByProvider, "google: Review; manual: 1:1, Dinner; morgen: Standup, Lunch"},
+		// Noon starts the afternoon and 17:00 the evening
+		{groupByTimeOfDay, "Morning: Standup, Lunch; Afternoon: Review, 1:1; Evening: Dinner"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			groups, err := groupEvents(events, tt.groupBy)
+			if err != nil {
+				t.Fatalf("groupEvents() error = %v", err)
+			}
+			if got := groupNames(events, groups); got != tt.want {
+				t.Errorf("groupEvents() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupEventsTimeOfDayOrder(t *testing.T) {
+	// Evening events come last even if their group would sort first by name
+	events := []models.CalendarEvent{testEvent("Review", "14:00", "15:00"), testEvent("Dinner", "18:00", "19:00")}
+	groups, err := groupEvents(events, groupByTimeOfDay)
+	if err != nil {
+		t.Fatalf("groupEvents() error = %v", err)
+	}
+	if got, want := groupNames(events, groups), "Afternoon: Review; Evening: Dinner"; got != want {
+		t.Errorf("groupEvents() = %s, want %s", got, want)
+	}
+}
+
+func TestGroupEventsUnsupported(t *testing.T) {
+	if _, err := groupEvents(nil, "weekday"); err == nil {
+		t.Error("groupEvents() should fail for an unsupported group by")
+	}
+}
+
+func TestAccountName(t *testing.T) {
+	tests := []struct {
+		event models.CalendarEvent
+		want  string
+	}{
+		{models.CalendarEvent{AccountID: "acc-1", AccountName: "me@work.com"}, "me@work.com"},
+		{models.CalendarEvent{AccountID: "acc-1"}, "acc-1"},
+		{models.CalendarEvent{}, ""},
+	}
+	for _, tt := range tests {
+		if got := accountName(tt.event); got != tt.want {
+			t.Errorf("accountName(%+v) = %q, want %q", tt.event, got, tt.want)
+		}
+	}
+}
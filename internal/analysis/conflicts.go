@@ -0,0 +1,65 @@
+package analysis
+
+import (
+	"time"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// Conflict describes two events that overlap each other, or that follow each
+// other without any gap when back-to-back detection is enabled.
+type Conflict struct {
+	// First and Second are indices into the slice passed to FindConflicts.
+	// First always starts no later than Second.
+	First  int
+	Second int
+	// Overlap is how long both events run at the same time. It is zero for
+	// back-to-back events.
+	Overlap time.Duration
+}
+
+// BackToBack reports whether the conflict is a zero-gap hand-over rather than
+// an actual overlap.
+func (c Conflict) BackToBack() bool {
+	return c.Overlap == 0
+}
+
+// FindConflicts returns every pair of overlapping events in the given list.
+// The events must be sorted by start time. If includeBackToBack is set, events
+// that start exactly when another one ends are reported as well. All-day events
+// never conflict.
+func FindConflicts(events []models.CalendarEvent, includeBackToBack bool) []Conflict {
+	var conflicts []Conflict
+	for i := range events {
+		first := events[i]
+		if first.AllDay {
+			continue
+		}
+		for j := i + 1; j < len(events); j++ {
+			second := events[j]
+			// Events are sorted, so nothing after this one can overlap either
+			if second.StartTime.After(first.EndTime) {
+				break
+			}
+			if second.AllDay {
+				continue
+			}
+
+			if second.StartTime.Before(first.EndTime) {
+				end := first.EndTime
+				if second.EndTime.Before(end) {
+					end = second.EndTime
+				}
+				if overlap := end.Sub(second.StartTime); overlap > 0 {
+					conflicts = append(conflicts, Conflict{First: i, Second: j, Overlap: overlap})
+				}
+				continue
+			}
+
+			if includeBackToBack && second.StartTime.Equal(first.EndTime) && second.EndTime.After(second.StartTime) {
+				conflicts = append(conflicts, Conflict{First: i, Second: j})
+			}
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+func event(title string, start, end string) models.CalendarEvent {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	parse := func(s string) time.Time {
+		t, err := time.Parse("15:04", s)
+		if err != nil {
+			panic(err)
+		}
+		return day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute)
+	}
+	return models.CalendarEvent{Title: title, StartTime: parse(start), EndTime: parse(end)}
+}
+
+func TestFindConflicts(t *testing.T) {
+	events := []models.CalendarEvent{
+		event("Standup", "09:00", "09:30"),
+		event("Review", "09:15", "10:00"),
+		event("Planning", "09:20", "09:25"),
+		event("1:1", "10:00", "10:30"),
+		event("Lunch", "12:00", "13:00"),
+	}
+
+	conflicts := FindConflicts(events, false)
+	want := []Conflict{
+		{First: 0, Second: 1, Overlap: 15 * time.Minute},
+		{First: 0, Second: 2, Overlap: 5 * time.Minute},
+		{First: 1, Second: 2, Overlap: 5 * time.Minute},
+	}
+	if len(conflicts) != len(want) {
+		t.Fatalf("expected %d conflicts, got %d: %+v", len(want), len(conflicts), conflicts)
+	}
+	for i := range want {
+		if conflicts[i] != want[i] {
+			t.Errorf("conflict %d: expected %+v, got %+v", i, want[i], conflicts[i])
+		}
+	}
+}
+
+func TestFindConflictsBackToBack(t *testing.T) {
+	events := []models.CalendarEvent{
+		event("Review", "09:15", "10:00"),
+		event("1:1", "10:00", "10:30"),
+		event("Lunch", "12:00", "13:00"),
+	}
+
+	if conflicts := FindConflicts(events, false); len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts without back-to-back detection, got %+v", conflicts)
+	}
+
+	conflicts := FindConflicts(events, true)
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %+v", conflicts)
+	}
+	if !conflicts[0].BackToBack() || conflicts[0].First != 0 || conflicts[0].Second != 1 {
+		t.Errorf("unexpected conflict %+v", conflicts[0])
+	}
+}
+
+func TestFindConflictsIgnoresAllDayEvents(t *testing.T) {
+	holiday := event("Holiday", "00:00", "00:00")
+	holiday.EndTime = holiday.StartTime.AddDate(0, 0, 1)
+	holiday.AllDay = true
+	events := []models.CalendarEvent{
+		holiday,
+		event("Standup", "09:00", "09:30"),
+	}
+
+	if conflicts := FindConflicts(events, true); len(conflicts) != 0 {
+		t.Errorf("expected no conflicts with all-day events, got %+v", conflicts)
+	}
+}
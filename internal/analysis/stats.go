@@ -0,0 +1,191 @@
+package analysis
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// unknownCalendar is used in the per calendar statistics for events without a calendar name.
+const unknownCalendar = "Other"
+
+// FocusBlock is a span of time within the working hours without any meetings.
+type FocusBlock struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the focus block.
+func (b FocusBlock) Duration() time.Duration {
+	return b.End.Sub(b.Start)
+}
+
+// CalendarTime is the time spent in meetings of a single calendar.
+type CalendarTime struct {
+	Name string
+	Time time.Duration
+}
+
+// DaySummary holds the meeting statistics of a single day.
+type DaySummary struct {
+	Date              time.Time
+	MeetingCount      int
+	MeetingTime       time.Duration
+	FirstMeeting      time.Time
+	LastMeeting       time.Time
+	LongestFocusBlock FocusBlock
+}
+
+// Summary holds the meeting statistics of a day or a range of days.
+type Summary struct {
+	Start        time.Time
+	End          time.Time
+	MeetingCount int
+	// MeetingTime is the time spent in meetings. Overlapping meetings are only counted once.
+	MeetingTime time.Duration
+	// FirstMeeting is the start of the earliest meeting in the range.
+	FirstMeeting time.Time
+	// LastMeeting is the end of the latest meeting in the range.
+	LastMeeting       time.Time
+	LongestFocusBlock FocusBlock
+	// PerCalendar holds the time spent in meetings per calendar, most time first. Overlapping
+	// meetings of a calendar are counted once, meetings of several calendars at the same time
+	// are counted for each of them, so the times may add up to more than MeetingTime.
+	PerCalendar []CalendarTime
+	Days        []DaySummary
+}
+
+// WorkingHours describes the part of the day used to find focus blocks as offsets from midnight.
+type WorkingHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Summarize computes the meeting statistics for the days in [start, end).
+// The events must be sorted by start time. Focus blocks are only searched for within the working hours.
+// All-day events, e.g. holidays, are not counted as meetings.
+func Summarize(events []models.CalendarEvent, start, end time.Time, hours WorkingHours) Summary {
+	summary := Summary{Start: start, End: end}
+	events = slices.DeleteFunc(slices.Clone(events), func(event models.CalendarEvent) bool {
+		return event.AllDay
+	})
+
+	// Overlapping meetings of a calendar are counted once like in MeetingTime
+	perCalendar := make(map[string][]models.CalendarEvent)
+	for _, event := range events {
+		name := event.CalendarName
+		if name == "" {
+			name = unknownCalendar
+		}
+		perCalendar[name] = append(perCalendar[name], event)
+	}
+	for name, calendarEvents := range perCalendar {
+		var spent time.Duration
+		for _, interval := range mergeIntervals(calendarEvents) {
+			spent += interval.End.Sub(interval.Start)
+		}
+		summary.PerCalendar = append(summary.PerCalendar, CalendarTime{Name: name, Time: spent})
+	}
+	sort.Slice(summary.PerCalendar, func(i, j int) bool {
+		if summary.PerCalendar[i].Time == summary.PerCalendar[j].Time {
+			return summary.PerCalendar[i].Name < summary.PerCalendar[j].Name
+		}
+		return summary.PerCalendar[i].Time > summary.PerCalendar[j].Time
+	})
+
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		nextDay := day.AddDate(0, 0, 1)
+		var dayEvents []models.CalendarEvent
+		for _, event := range events {
+			if !event.StartTime.Before(day) && event.StartTime.Before(nextDay) {
+				dayEvents = append(dayEvents, event)
+			}
+		}
+
+		daySummary := summarizeDay(day, dayEvents, hours)
+		summary.Days = append(summary.Days, daySummary)
+
+		summary.MeetingCount += daySummary.MeetingCount
+		summary.MeetingTime += daySummary.MeetingTime
+		if daySummary.MeetingCount > 0 {
+			if summary.FirstMeeting.IsZero() || daySummary.FirstMeeting.Before(summary.FirstMeeting) {
+				summary.FirstMeeting = daySummary.FirstMeeting
+			}
+			if daySummary.LastMeeting.After(summary.LastMeeting) {
+				summary.LastMeeting = daySummary.LastMeeting
+			}
+		}
+		if daySummary.LongestFocusBlock.Duration() > summary.LongestFocusBlock.Duration() {
+			summary.LongestFocusBlock = daySummary.LongestFocusBlock
+		}
+	}
+
+	return summary
+}
+
+// summarizeDay computes the statistics for the events of a single day.
+func summarizeDay(day time.Time, events []models.CalendarEvent, hours WorkingHours) DaySummary {
+	summary := DaySummary{Date: day, MeetingCount: len(events)}
+
+	busy := mergeIntervals(events)
+	for _, interval := range busy {
+		summary.MeetingTime += interval.End.Sub(interval.Start)
+	}
+	if len(busy) > 0 {
+		summary.FirstMeeting = busy[0].Start
+		summary.LastMeeting = busy[len(busy)-1].End
+	}
+
+	// Find the largest gap between meetings within the working hours
+	workStart := wallClock(day, hours.Start)
+	workEnd := wallClock(day, hours.End)
+	cursor := workStart
+	for _, interval := range append(busy, busyInterval{Start: workEnd, End: workEnd}) {
+		gapEnd := interval.Start
+		if gapEnd.After(workEnd) {
+			gapEnd = workEnd
+		}
+		if gapEnd.Sub(cursor) > summary.LongestFocusBlock.Duration() {
+			summary.LongestFocusBlock = FocusBlock{Start: cursor, End: gapEnd}
+		}
+		if interval.End.After(cursor) {
+			cursor = interval.End
+		}
+		if !cursor.Before(workEnd) {
+			break
+		}
+	}
+
+	return summary
+}
+
+// wallClock returns the time of day at the given offset from midnight on the clock of the day's timezone.
+// Unlike day.Add it keeps 9:00 at 9:00 on days on which daylight saving time starts or ends.
+func wallClock(day time.Time, offset time.Duration) time.Time {
+	year, month, date := day.Date()
+	hour, minute := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	return time.Date(year, month, date, hour, minute, 0, 0, day.Location())
+}
+
+// busyInterval is a span of time covered by one or more meetings.
+type busyInterval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// mergeIntervals merges the times of the sorted events into non-overlapping busy intervals.
+func mergeIntervals(events []models.CalendarEvent) []busyInterval {
+	var merged []busyInterval
+	for _, event := range events {
+		if n := len(merged); n > 0 && !event.StartTime.After(merged[n-1].End) {
+			if event.EndTime.After(merged[n-1].End) {
+				merged[n-1].End = event.EndTime
+			}
+			continue
+		}
+		merged = append(merged, busyInterval{Start: event.StartTime, End: event.EndTime})
+	}
+	return merged
+}
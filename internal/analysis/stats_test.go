@@ -0,0 +1,107 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+func TestSummarize(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events := []models.CalendarEvent{
+		event("Standup", "09:00", "09:30"),
+		event("Review", "09:15", "10:00"),
+		event("1:1", "11:00", "11:30"),
+		event("Planning", "15:00", "16:00"),
+	}
+	events[0].CalendarName = "Work"
+	events[1].CalendarName = "Work"
+	events[3].CalendarName = "Personal"
+
+	summary := Summarize(events, day, day.AddDate(0, 0, 1), WorkingHours{Start: 9 * time.Hour, End: 17 * time.Hour})
+
+	if summary.MeetingCount != 4 {
+		t.Errorf("expected 4 meetings, got %d", summary.MeetingCount)
+	}
+	if summary.MeetingTime != 2*time.Hour+30*time.Minute {
+		t.Errorf("expected 2h30m of meetings, got %s", summary.MeetingTime)
+	}
+	if !summary.FirstMeeting.Equal(day.Add(9*time.Hour)) || !summary.LastMeeting.Equal(day.Add(16*time.Hour)) {
+		t.Errorf("unexpected first/last meeting %s/%s", summary.FirstMeeting, summary.LastMeeting)
+	}
+
+	focus := summary.LongestFocusBlock
+	if !focus.Start.Equal(day.Add(11*time.Hour+30*time.Minute)) || focus.Duration() != 3*time.Hour+30*time.Minute {
+		t.Errorf("unexpected focus block %s-%s", focus.Start, focus.End)
+	}
+
+	// Standup and Review overlap, so Work is busy for an hour only
+	want := []CalendarTime{
+		{Name: "Personal", Time: time.Hour},
+		{Name: "Work", Time: time.Hour},
+		{Name: unknownCalendar, Time: 30 * time.Minute},
+	}
+	if len(summary.PerCalendar) != len(want) {
+		t.Fatalf("expected %d calendars, got %+v", len(want), summary.PerCalendar)
+	}
+	for i := range want {
+		if summary.PerCalendar[i] != want[i] {
+			t.Errorf("calendar %d: expected %+v, got %+v", i, want[i], summary.PerCalendar[i])
+		}
+	}
+}
+
+func TestSummarizeEmptyDay(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	summary := Summarize(nil, day, day.AddDate(0, 0, 2), WorkingHours{Start: 9 * time.Hour, End: 17 * time.Hour})
+
+	if len(summary.Days) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(summary.Days))
+	}
+	if summary.LongestFocusBlock.Duration() != 8*time.Hour {
+		t.Errorf("expected a full day of focus time, got %s", summary.LongestFocusBlock.Duration())
+	}
+	if !summary.FirstMeeting.IsZero() {
+		t.Errorf("expected no first meeting, got %s", summary.FirstMeeting)
+	}
+}
+
+func TestSummarizeIgnoresAllDayEvents(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	holiday := event("Holiday", "00:00", "00:00")
+	holiday.EndTime = day.AddDate(0, 0, 1)
+	holiday.AllDay = true
+	events := []models.CalendarEvent{holiday, event("Standup", "09:00", "09:30")}
+
+	summary := Summarize(events, day, day.AddDate(0, 0, 1), WorkingHours{Start: 9 * time.Hour, End: 17 * time.Hour})
+	if summary.MeetingCount != 1 || summary.MeetingTime != 30*time.Minute {
+		t.Errorf("expected only the standup to be counted, got %d meetings, %s", summary.MeetingCount, summary.MeetingTime)
+	}
+	if !events[0].AllDay {
+		t.Error("Summarize modified the events")
+	}
+}
+
+func TestSummarizeDaylightSavingDay(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+	// Clocks go forward from 2:00 to 3:00, so the day only has 23 hours
+	day := time.Date(2025, 3, 30, 0, 0, 0, 0, berlin)
+	standup := models.CalendarEvent{
+		Title:     "Standup",
+		StartTime: time.Date(2025, 3, 30, 14, 0, 0, 0, berlin),
+		EndTime:   time.Date(2025, 3, 30, 15, 0, 0, 0, berlin),
+	}
+
+	summary := Summarize([]models.CalendarEvent{standup}, day, day.AddDate(0, 0, 1), WorkingHours{Start: 9 * time.Hour, End: 17 * time.Hour})
+
+	focus := summary.LongestFocusBlock
+	wantStart := time.Date(2025, 3, 30, 9, 0, 0, 0, berlin)
+	wantEnd := time.Date(2025, 3, 30, 14, 0, 0, 0, berlin)
+	if !focus.Start.Equal(wantStart) || !focus.End.Equal(wantEnd) {
+		t.Errorf("expected the focus block 09:00-14:00, got %s-%s", focus.Start.Format("15:04"), focus.End.Format("15:04"))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/kirsle/configdir"
 	"gopkg.in/yaml.v3"
@@ -13,30 +14,148 @@ const CURRENT_CONFIG_VERSION uint64 = 1
 const CONFIG_FILE_NAME string = "agenda.conf"
 const CONFIG_FOLDER string = "agenda"
 
+const DEFAULT_DESCRIPTION_MAX_LENGTH int = 120
+const DEFAULT_WORKDAY_START string = "09:00"
+const DEFAULT_WORKDAY_END string = "17:00"
+
+// DEFAULT_STATS_TEMPLATE is the template used by the stats command if none is configured.
+const DEFAULT_STATS_TEMPLATE string = `Meetings: {{.MeetingCount}}
+Meeting time: {{duration .MeetingTime}}
+Longest focus block: {{duration .LongestFocusBlock.Duration}}{{if .LongestFocusBlock.Duration}} ({{formatTime .LongestFocusBlock.Start}}-{{formatTime .LongestFocusBlock.End}}){{end}}
+{{- if .MeetingCount}}
+First meeting: {{formatTime .FirstMeeting}}
+Last meeting: {{formatTime .LastMeeting}}
+Time per calendar:
+{{- range .PerCalendar}}
+- {{.Name}}: {{duration .Time}}
+{{- end}}
+{{- end}}
+{{- if gt (len .Days) 1}}
+Per day:
+{{- range .Days}}
+- {{formatDate .Date}}: {{.MeetingCount}} meetings, {{duration .MeetingTime}}
+{{- end}}
+{{- end}}`
+
+// DEFAULT_GROUP_HEADING_TEMPLATE is the template used for group headings if none is configured.
+const DEFAULT_GROUP_HEADING_TEMPLATE string = "## {{.Name}}"
+
+// DEFAULT_DAY_HEADING_TEMPLATE is the template used for day headings if none is configured.
+const DEFAULT_DAY_HEADING_TEMPLATE string = "## {{formatDate .Date}}"
+
+// DEFAULT_TASK_TEMPLATE is the template used for tasks if none is configured.
+const DEFAULT_TASK_TEMPLATE string = "- [{{if .Completed}}x{{else}} {{end}}] {{.Title}}" +
+	"{{if .Overdue}} (overdue since {{formatDate .Due}}){{else if not .Scheduled.IsZero}} ({{formatTime .Scheduled}}){{end}}"
+
+// DEFAULT_TASK_HEADING_TEMPLATE is the template used for the heading of the tasks if none is configured.
+const DEFAULT_TASK_HEADING_TEMPLATE string = "## Tasks"
+
 // Config represents the application configuration
 type Config struct {
-	Provider      string                    `yaml:"provider"`
-	TimeFormat    string                    `yaml:"time_format"`
-	EventTemplate string                    `yaml:"event_template"`
-	Providers     map[string]ProviderConfig `yaml:"providers"`
-	Version       uint64                    `yaml:"config_version"`
+	Provider string `yaml:"provider"`
+	// MergeProviders are further providers whose events are merged with the ones of Provider.
+	MergeProviders []string `yaml:"merge_providers,omitempty"`
+	// TimeFormat is a Go layout or one of the named formats 24h, 12h or iso.
+	TimeFormat string `yaml:"time_format"`
+	// DateFormat is used for day headings: short, long, iso or a Go layout.
+	DateFormat string `yaml:"date_format"`
+	// Locale is the language of day and month names: en, de, fr or es.
+	Locale string `yaml:"locale"`
+	// Timezone is the IANA name of the timezone the agenda is rendered in, the system timezone is used if empty.
+	Timezone string `yaml:"timezone"`
+	// ExtraTimezones are IANA timezone names each event is additionally shown in.
+	ExtraTimezones []string `yaml:"extra_timezones"`
+	EventTemplate  string   `yaml:"event_template"`
+	// SummaryTemplate is rendered after the events. Nothing is printed if it is empty.
+	SummaryTemplate string `yaml:"summary_template"`
+	// GroupBy splits the agenda into sections: calendar, account, provider or morning-afternoon.
+	GroupBy              string `yaml:"group_by"`
+	GroupHeadingTemplate string `yaml:"group_heading_template"`
+	// DayHeadingTemplate is printed before the events of each day when the agenda spans multiple days.
+	DayHeadingTemplate string `yaml:"day_heading_template"`
+	// DescriptionStripPatterns are regular expressions for boilerplate removed from event descriptions.
+	// The built-in patterns are used if the option is not set, an empty list disables stripping.
+	DescriptionStripPatterns []string `yaml:"description_strip_patterns,omitempty"`
+	// DescriptionMaxLength is the length of the short description, 0 disables truncation.
+	DescriptionMaxLength int `yaml:"description_max_length"`
+	// ShowTasks adds the tasks due or scheduled in the range of the agenda, and overdue tasks, after the events.
+	ShowTasks           bool   `yaml:"show_tasks"`
+	TaskTemplate        string `yaml:"task_template"`
+	TaskHeadingTemplate string `yaml:"task_heading_template"`
+	// Pretty enables colored terminal output. Markdown is still used when stdout is not a terminal.
+	Pretty        bool   `yaml:"pretty"`
+	StatsTemplate string `yaml:"stats_template"`
+	// WorkdayStart and WorkdayEnd are used to find focus blocks, format: HH:MM
+	WorkdayStart string                    `yaml:"workday_start"`
+	WorkdayEnd   string                    `yaml:"workday_end"`
+	Providers    map[string]ProviderConfig `yaml:"providers"`
+	Version      uint64                    `yaml:"config_version"`
 }
 
 // ProviderConfig holds provider-specific configuration
 type ProviderConfig struct {
-	BaseURL           string            `yaml:"base_url"`
-	Headers           map[string]string `yaml:"headers"`
-	EnvAPIKey         string            `yaml:"env_api_key"`
-	CalendarsToIgnore []string          `yaml:"calendars_to_ignore"`
+	// Type is the kind of provider, e.g. morgen or google. It defaults to the name of the provider, so several
+	// instances of the same type can be configured under different names.
+	Type      string            `yaml:"type,omitempty"`
+	BaseURL   string            `yaml:"base_url"`
+	Headers   map[string]string `yaml:"headers"`
+	EnvAPIKey string            `yaml:"env_api_key"`
+	// APIKeyCommand is run to print the API key, e.g. "pass show morgen". It takes precedence over APIKeyFile and EnvAPIKey.
+	APIKeyCommand string `yaml:"api_key_command"`
+	// APIKeyFile is a file holding the API key, it must only be readable by its owner. It takes precedence over EnvAPIKey.
+	APIKeyFile        string   `yaml:"api_key_file"`
+	CalendarsToIgnore []string `yaml:"calendars_to_ignore"`
+	// DefaultCalendar is the calendar new events are created in if none is given.
+	DefaultCalendar string `yaml:"default_calendar,omitempty"`
+	// QueryParams are added to every request. Like headers they may reference {API_KEY} and ${VAR}.
+	QueryParams map[string]string `yaml:"query_params"`
+	// URLs are the iCalendar feeds read by the ics provider. Like headers they may reference {API_KEY} and ${VAR}.
+	URLs []string `yaml:"urls,omitempty"`
+	// Paths are the local files or directories read by a provider: the vdir collections of the vdir provider,
+	// each subdirectory of a collection is a calendar, or the event files of the manual provider.
+	Paths []string `yaml:"paths,omitempty"`
+	// Command is the executable run by the exec provider, with Args as its arguments.
+	Command string   `yaml:"command,omitempty"`
+	Args    []string `yaml:"args,omitempty"`
+	// Timeout limits how long the exec provider waits for its command, e.g. "10s". Defaults to 30s.
+	Timeout string `yaml:"timeout,omitempty"`
+	// Options are passed unchanged to the command of the exec provider.
+	Options map[string]string `yaml:"options,omitempty"`
+	// OAuth configures an OAuth2 authorization-code flow, see the auth command. The access token
+	// is sent as a bearer token unless an Authorization header is configured.
+	OAuth *OAuthConfig `yaml:"oauth,omitempty"`
+	// Name is the name the provider is configured under, it is set when the provider is created.
+	Name string `yaml:"-"`
+}
+
+// OAuthConfig holds the OAuth2 client registration of a provider.
+type OAuthConfig struct {
+	ClientID string `yaml:"client_id"`
+	// ClientSecret is optional for public clients that rely on PKCE alone.
+	ClientSecret string   `yaml:"client_secret"`
+	AuthURL      string   `yaml:"auth_url"`
+	TokenURL     string   `yaml:"token_url"`
+	Scopes       []string `yaml:"scopes"`
+	// AuthParams are added to the authorization URL, e.g. access_type=offline for Google.
+	AuthParams map[string]string `yaml:"auth_params"`
+	// RedirectPort is the loopback port receiving the authorization code, a free port is used if 0.
+	RedirectPort int `yaml:"redirect_port"`
 }
 
 // Returns the default configuration for the application.
 func DefaultConfig() Config {
 	// Default configuration for now
 	config := Config{
-		Provider:      "morgen",
-		TimeFormat:    "15:04",
-		EventTemplate: "- {{.StartTimeFormatted}}-{{.EndTimeFormatted}}: {{.Title}}",
+		Provider:             "morgen",
+		TimeFormat:           "15:04",
+		EventTemplate:        "- {{.StartTimeFormatted}}-{{.EndTimeFormatted}}: {{.Title}}",
+		StatsTemplate:        DEFAULT_STATS_TEMPLATE,
+		GroupHeadingTemplate: DEFAULT_GROUP_HEADING_TEMPLATE,
+		TaskTemplate:         DEFAULT_TASK_TEMPLATE,
+		TaskHeadingTemplate:  DEFAULT_TASK_HEADING_TEMPLATE,
+		DescriptionMaxLength: DEFAULT_DESCRIPTION_MAX_LENGTH,
+		WorkdayStart:         DEFAULT_WORKDAY_START,
+		WorkdayEnd:           DEFAULT_WORKDAY_END,
 		Providers: map[string]ProviderConfig{
 			"morgen": {
 				BaseURL: "https://api.morgen.so/v3",
@@ -47,6 +166,25 @@ func DefaultConfig() Config {
 				EnvAPIKey:         "MORGEN_API_KEY",
 				CalendarsToIgnore: []string{"ignore_this_calendar"},
 			},
+			"google": {
+				BaseURL: "https://www.googleapis.com/calendar/v3",
+				OAuth: &OAuthConfig{
+					AuthURL:  "https://accounts.google.com/o/oauth2/v2/auth",
+					TokenURL: "https://oauth2.googleapis.com/token",
+					Scopes:   []string{"https://www.googleapis.com/auth/calendar.readonly"},
+					// Google only issues a refresh token with offline access
+					AuthParams: map[string]string{"access_type": "offline", "prompt": "consent"},
+				},
+			},
+			"graph": {
+				BaseURL: "https://graph.microsoft.com/v1.0",
+				OAuth: &OAuthConfig{
+					AuthURL:  "https://login.microsoftonline.com/common/oauth2/v2.0/authorize",
+					TokenURL: "https://login.microsoftonline.com/common/oauth2/v2.0/token",
+					// offline_access is needed for a refresh token
+					Scopes: []string{"offline_access", "Calendars.Read"},
+				},
+			},
 		},
 		Version: CURRENT_CONFIG_VERSION,
 	}
@@ -54,6 +192,53 @@ func DefaultConfig() Config {
 	return config
 }
 
+// Location returns the configured timezone, or the system timezone if none is set.
+func (c Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(c.Timezone)
+}
+
+// WorkingHours returns the start and end of the working day as offsets from midnight.
+// Defaults are used for values that are not set.
+func (c Config) WorkingHours() (time.Duration, time.Duration, error) {
+	start, err := parseClockTime(c.WorkdayStart, DEFAULT_WORKDAY_START)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid workday_start: %w", err)
+	}
+	end, err := parseClockTime(c.WorkdayEnd, DEFAULT_WORKDAY_END)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid workday_end: %w", err)
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("workday_end %s must be after workday_start %s", c.WorkdayEnd, c.WorkdayStart)
+	}
+	return start, end, nil
+}
+
+// parseClockTime parses a HH:MM time of day into an offset from midnight.
+func parseClockTime(value, fallback string) (time.Duration, error) {
+	if value == "" {
+		value = fallback
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// CacheDir returns the directory downloaded data, e.g. calendar feeds, is cached in.
+func CacheDir() string {
+	return configdir.LocalCache(CONFIG_FOLDER)
+}
+
+// TokenDir returns the directory OAuth tokens are stored in.
+func TokenDir() string {
+	return filepath.Join(configdir.LocalConfig(CONFIG_FOLDER), "tokens")
+}
+
 // DefaultConfigPath returns the default path for the configuration file.
 func DefaultConfigPath() string {
 	return getSystemConfigPath()
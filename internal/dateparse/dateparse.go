@@ -0,0 +1,181 @@
+// Package dateparse parses the date arguments accepted on the command line.
+//
+// The following formats are supported, all case-insensitive:
+//
+//   - ISO dates: 2025-03-10
+//   - today, tomorrow, yesterday
+//   - weekday names or abbreviations: monday, fri. A bare weekday is the next
+//     such day, today included. "next fri" skips today and "last fri" is the
+//     most recent one before today.
+//   - relative offsets in days, weeks, months or years: +2d, -1w, +1m, -1y
+//   - ISO weeks: 2025-W03 for the whole week or 2025-W03-2 for a single day
+//   - this week, next week, last week
+//
+// Dates are resolved relative to a reference time and returned at midnight in
+// the location of that reference time.
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isoDateLayout is the layout of plain ISO dates.
+const isoDateLayout = "2006-01-02"
+
+var (
+	relativePattern = regexp.MustCompile(`^([+-])(\d+)([dwmy]?)$`)
+	isoWeekPattern  = regexp.MustCompile(`^(\d{4})-?w(\d{1,2})(?:-?([1-7]))?$`)
+)
+
+// weekdays maps the names and abbreviations of the days of the week.
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// Parse parses a single date relative to now. Inputs describing a range, like
+// an ISO week, resolve to the first day of the range.
+func Parse(input string, now time.Time) (time.Time, error) {
+	start, _, err := ParseRange(input, now)
+	return start, err
+}
+
+// ParseRange parses a date or a range of dates relative to now.
+// It returns the start of the first day and the start of the day after the last
+// day, so single dates result in a range of one day.
+func ParseRange(input string, now time.Time) (time.Time, time.Time, error) {
+	value := strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	today := startOfDay(now)
+
+	day := func(date time.Time) (time.Time, time.Time, error) {
+		return date, date.AddDate(0, 0, 1), nil
+	}
+
+	switch value {
+	case "":
+		return time.Time{}, time.Time{}, fmt.Errorf("empty date")
+	case "today", "now":
+		return day(today)
+	case "tomorrow":
+		return day(today.AddDate(0, 0, 1))
+	case "yesterday":
+		return day(today.AddDate(0, 0, -1))
+	case "this week":
+		monday := startOfWeek(today)
+		return monday, monday.AddDate(0, 0, 7), nil
+	case "next week":
+		monday := startOfWeek(today).AddDate(0, 0, 7)
+		return monday, monday.AddDate(0, 0, 7), nil
+	case "last week":
+		monday := startOfWeek(today).AddDate(0, 0, -7)
+		return monday, monday.AddDate(0, 0, 7), nil
+	}
+
+	if date, err := time.ParseInLocation(isoDateLayout, value, now.Location()); err == nil {
+		return day(date)
+	}
+
+	if match := relativePattern.FindStringSubmatch(value); match != nil {
+		amount, err := strconv.Atoi(match[2])
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid offset %q: %w", input, err)
+		}
+		if match[1] == "-" {
+			amount = -amount
+		}
+		switch match[3] {
+		case "", "d":
+			return day(today.AddDate(0, 0, amount))
+		case "w":
+			return day(today.AddDate(0, 0, 7*amount))
+		case "m":
+			return day(today.AddDate(0, amount, 0))
+		case "y":
+			return day(today.AddDate(amount, 0, 0))
+		}
+	}
+
+	if match := isoWeekPattern.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		week, _ := strconv.Atoi(match[2])
+		monday, err := isoWeekStart(year, week, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid week %q: %w", input, err)
+		}
+		if match[3] != "" {
+			weekday, _ := strconv.Atoi(match[3])
+			return day(monday.AddDate(0, 0, weekday-1))
+		}
+		return monday, monday.AddDate(0, 0, 7), nil
+	}
+
+	if date, ok := parseWeekday(value, today); ok {
+		return day(date)
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("unrecognized date %q, use YYYY-MM-DD, today, tomorrow, yesterday, a weekday, +Nd/-Nw or YYYY-Www", input)
+}
+
+// parseWeekday resolves "monday", "next monday" and "last monday" relative to today.
+func parseWeekday(value string, today time.Time) (time.Time, bool) {
+	modifier, name, found := strings.Cut(value, " ")
+	if !found {
+		modifier, name = "", value
+	}
+
+	weekday, ok := weekdays[name]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	daysAhead := (int(weekday) - int(today.Weekday()) + 7) % 7
+	switch modifier {
+	case "", "this":
+		return today.AddDate(0, 0, daysAhead), true
+	case "next":
+		if daysAhead == 0 {
+			daysAhead = 7
+		}
+		return today.AddDate(0, 0, daysAhead), true
+	case "last":
+		daysBack := (int(today.Weekday()) - int(weekday) + 7) % 7
+		if daysBack == 0 {
+			daysBack = 7
+		}
+		return today.AddDate(0, 0, -daysBack), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// isoWeekStart returns the Monday of the given ISO 8601 week.
+func isoWeekStart(year, week int, loc *time.Location) (time.Time, error) {
+	// December 28th is always in the last week of the year
+	if _, weeks := time.Date(year, time.December, 28, 0, 0, 0, 0, loc).ISOWeek(); week < 1 || week > weeks {
+		return time.Time{}, fmt.Errorf("%d has %d weeks", year, weeks)
+	}
+
+	// January 4th is always in the first week of the year
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	return startOfWeek(jan4).AddDate(0, 0, 7*(week-1)), nil
+}
+
+// startOfWeek returns the Monday of the week of the given date.
+func startOfWeek(date time.Time) time.Time {
+	daysSinceMonday := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -daysSinceMonday)
+}
+
+// startOfDay returns midnight of the day of the given time in its location.
+func startOfDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
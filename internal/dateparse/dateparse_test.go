@@ -0,0 +1,109 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+// now is a Wednesday afternoon in a timezone far away from UTC.
+var now = time.Date(2025, time.March, 12, 15, 30, 0, 0, time.FixedZone("UTC-8", -8*60*60))
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{"2025-03-10", date(2025, time.March, 10), date(2025, time.March, 11)},
+		{"today", date(2025, time.March, 12), date(2025, time.March, 13)},
+		{"  Today ", date(2025, time.March, 12), date(2025, time.March, 13)},
+		{"tomorrow", date(2025, time.March, 13), date(2025, time.March, 14)},
+		{"yesterday", date(2025, time.March, 11), date(2025, time.March, 12)},
+
+		// Weekdays
+		{"wednesday", date(2025, time.March, 12), date(2025, time.March, 13)},
+		{"friday", date(2025, time.March, 14), date(2025, time.March, 15)},
+		{"mon", date(2025, time.March, 17), date(2025, time.March, 18)},
+		{"this thu", date(2025, time.March, 13), date(2025, time.March, 14)},
+		{"next wed", date(2025, time.March, 19), date(2025, time.March, 20)},
+		{"next fri", date(2025, time.March, 14), date(2025, time.March, 15)},
+		{"last wed", date(2025, time.March, 5), date(2025, time.March, 6)},
+		{"last Monday", date(2025, time.March, 10), date(2025, time.March, 11)},
+
+		// Relative offsets
+		{"+2d", date(2025, time.March, 14), date(2025, time.March, 15)},
+		{"+2", date(2025, time.March, 14), date(2025, time.March, 15)},
+		{"-1w", date(2025, time.March, 5), date(2025, time.March, 6)},
+		{"+1m", date(2025, time.April, 12), date(2025, time.April, 13)},
+		{"-1y", date(2024, time.March, 12), date(2024, time.March, 13)},
+		{"-20d", date(2025, time.February, 20), date(2025, time.February, 21)},
+
+		// ISO weeks
+		{"2025-W11", date(2025, time.March, 10), date(2025, time.March, 17)},
+		{"2025w11", date(2025, time.March, 10), date(2025, time.March, 17)},
+		{"2025-W11-3", date(2025, time.March, 12), date(2025, time.March, 13)},
+		{"2025-W01", date(2024, time.December, 30), date(2025, time.January, 6)},
+		{"2026-W53", date(2026, time.December, 28), date(2027, time.January, 4)},
+		{"this week", date(2025, time.March, 10), date(2025, time.March, 17)},
+		{"next week", date(2025, time.March, 17), date(2025, time.March, 24)},
+		{"last week", date(2025, time.March, 3), date(2025, time.March, 10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			start, end, err := ParseRange(tt.input, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("ParseRange(%q) = %s - %s, want %s - %s", tt.input, start, end, tt.wantStart, tt.wantEnd)
+			}
+			if start.Location() != now.Location() {
+				t.Errorf("expected location %s, got %s", now.Location(), start.Location())
+			}
+		})
+	}
+}
+
+func TestParseRangeAcrossDaylightSavingTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	// Clocks go forward on March 30th 2025 in Berlin, so the day is only 23 hours long
+	start, end, err := ParseRange("tomorrow", time.Date(2025, time.March, 29, 12, 0, 0, 0, berlin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, time.March, 30, 0, 0, 0, 0, berlin); !start.Equal(want) {
+		t.Errorf("expected start %s, got %s", want, start)
+	}
+	if want := time.Date(2025, time.March, 31, 0, 0, 0, 0, berlin); !end.Equal(want) {
+		t.Errorf("expected end %s, got %s", want, end)
+	}
+}
+
+func TestParse(t *testing.T) {
+	got, err := Parse("2025-W11", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := date(2025, time.March, 10); !got.Equal(want) {
+		t.Errorf("Parse() = %s, want %s", got, want)
+	}
+}
+
+func TestParseRangeInvalid(t *testing.T) {
+	for _, input := range []string{"", "someday", "2025-13-01", "2025-02-30", "2025-W54", "2025-W00", "+2h", "next", "next month", "first monday"} {
+		t.Run(input, func(t *testing.T) {
+			if _, _, err := ParseRange(input, now); err == nil {
+				t.Errorf("expected an error for %q", input)
+			}
+		})
+	}
+}
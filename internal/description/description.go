@@ -0,0 +1,163 @@
+package description
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultStripPatterns remove the boilerplate that conferencing tools add to invites.
+// They are applied to the description after it has been converted to markdown.
+var DefaultStripPatterns = []string{
+	// Google Calendar adds a block framed by "-::~:~::~..." lines with the Meet details
+	`(?s)-::~[:~]+::-.*?(-::~[:~]+::-|$)`,
+	// Meet details without the frame end with the "Learn more" link, otherwise only the join line is removed
+	`(?s)Join with Google Meet.*?Learn more about Meet at:?[ \t]*\S*`,
+	`(?m)^.*Join with Google Meet.*$`,
+	// Zoom invite body, it ends with the link to the local dial-in numbers
+	`(?s)((─|_){10,}\s*)?Join Zoom Meeting.*?Find your local number:?[ \t]*\S*`,
+	`(?m)^.*Join Zoom Meeting.*(\n[ \t]*https://\S*zoom\.us/\S*)?$`,
+	// Microsoft Teams invite footer, framed by lines of underscores
+	`(?s)_{10,}\s*Microsoft Teams.*?(_{10,}|$)`,
+	// Dial-in details
+	`(?im)^.*(\bdial-in\b|\bjoin by phone\b|\bone tap mobile\b|\b(meeting id|passcode|pin)\s*:).*$`,
+}
+
+// linkPattern matches HTML anchors, the href and the text are captured.
+var linkPattern = regexp.MustCompile(`(?is)<a\s[^>]*?href\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a>`)
+
+// dropPattern matches elements that are removed including their content.
+var dropPattern = regexp.MustCompile(`(?is)<(style|script|head|title)[^>]*>.*?</(style|script|head|title)>`)
+
+// tagPattern matches any remaining HTML tag. The tag name is captured along with a leading slash for closing tags.
+var tagPattern = regexp.MustCompile(`(?s)<(/?[a-zA-Z][a-zA-Z0-9]*)[^>]*>`)
+
+// commentPattern matches HTML comments.
+var commentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// blankLinesPattern matches runs of blank lines.
+var blankLinesPattern = regexp.MustCompile(`\n[ \t]*(\n[ \t]*)+`)
+
+// spacesPattern matches runs of spaces and tabs.
+var spacesPattern = regexp.MustCompile(`[ \t\x{00a0}]+`)
+
+// Normalizer cleans up event descriptions for use in markdown output.
+type Normalizer struct {
+	stripPatterns []*regexp.Regexp
+	maxLength     int
+}
+
+// NewNormalizer creates a Normalizer that removes text matching any of the patterns and
+// truncates short descriptions to maxLength characters. If patterns is nil the
+// DefaultStripPatterns are used.
+func NewNormalizer(patterns []string, maxLength int) (*Normalizer, error) {
+	if patterns == nil {
+		patterns = DefaultStripPatterns
+	}
+
+	normalizer := &Normalizer{maxLength: maxLength}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid description strip pattern %q: %w", pattern, err)
+		}
+		normalizer.stripPatterns = append(normalizer.stripPatterns, re)
+	}
+	return normalizer, nil
+}
+
+// Normalize converts the description to markdown and removes boilerplate.
+func (n *Normalizer) Normalize(description string) string {
+	text := ToMarkdown(description)
+	for _, pattern := range n.stripPatterns {
+		text = pattern.ReplaceAllString(text, "")
+	}
+	return tidy(text)
+}
+
+// Short returns the normalized description on a single line, truncated to the configured maximum length.
+func (n *Normalizer) Short(description string) string {
+	text := strings.Join(strings.Fields(n.Normalize(description)), " ")
+	if n.maxLength <= 0 || utf8.RuneCountInString(text) <= n.maxLength {
+		return text
+	}
+
+	runes := []rune(text)
+	truncated := strings.TrimRight(string(runes[:n.maxLength]), " ")
+	// Avoid cutting words in half if there is a space to break at
+	if i := strings.LastIndex(truncated, " "); i > len(truncated)/2 {
+		truncated = truncated[:i]
+	}
+	return strings.TrimRight(truncated, " .,;:") + "…"
+}
+
+// ToMarkdown converts an HTML description into markdown. Descriptions without HTML tags
+// are returned with only their whitespace tidied up.
+func ToMarkdown(description string) string {
+	if !tagPattern.MatchString(description) {
+		return tidy(description)
+	}
+
+	text := commentPattern.ReplaceAllString(description, "")
+	text = dropPattern.ReplaceAllString(text, "")
+	// Newlines in HTML are just whitespace
+	text = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+
+	text = linkPattern.ReplaceAllStringFunc(text, func(anchor string) string {
+		match := linkPattern.FindStringSubmatch(anchor)
+		href := html.UnescapeString(match[1])
+		label := strings.TrimSpace(tagPattern.ReplaceAllString(match[2], ""))
+		label = html.UnescapeString(label)
+		if label == "" || label == href || strings.HasPrefix(href, "mailto:") && strings.TrimPrefix(href, "mailto:") == label {
+			return href
+		}
+		return fmt.Sprintf("[%s](%s)", label, href)
+	})
+
+	text = tagPattern.ReplaceAllStringFunc(text, func(tag string) string {
+		name := strings.ToLower(tagPattern.FindStringSubmatch(tag)[1])
+		switch name {
+		case "br":
+			return "\n"
+		case "p", "/p", "div", "/div", "ul", "/ul", "ol", "/ol", "table", "/table", "tr":
+			return "\n\n"
+		case "li":
+			return "\n- "
+		case "b", "/b", "strong", "/strong":
+			return "**"
+		case "i", "/i", "em", "/em":
+			return "_"
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			return "\n\n" + strings.Repeat("#", int(name[1]-'0')) + " "
+		case "/h1", "/h2", "/h3", "/h4", "/h5", "/h6":
+			return "\n\n"
+		case "hr":
+			return "\n\n---\n\n"
+		case "td", "th":
+			return " "
+		default:
+			return ""
+		}
+	})
+
+	return tidy(html.UnescapeString(text))
+}
+
+// tidy collapses repeated spaces and blank lines and trims every line.
+func tidy(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = spacesPattern.ReplaceAllString(text, " ")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = strings.Join(lines, "\n")
+
+	// Empty emphasis markers are left over from formatted whitespace
+	text = strings.NewReplacer("****", "", "** **", " ").Replace(text)
+	text = blankLinesPattern.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
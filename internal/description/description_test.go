@@ -0,0 +1,118 @@
+package description
+
+import "testing"
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "plain text",
+			in:   "Agenda:\n\n\n  1. Intro   \n2. Demo",
+			want: "Agenda:\n\n1. Intro\n2. Demo",
+		},
+		{
+			name: "paragraphs and formatting",
+			in:   "<p>Hi <b>team</b>,</p><p>see <a href=\"https://example.com/doc?a=1&amp;b=2\">the doc</a><br>thanks &amp; bye</p>",
+			want: "Hi **team**,\n\nsee [the doc](https://example.com/doc?a=1&b=2)\nthanks & bye",
+		},
+		{
+			name: "lists and bare links",
+			in:   "<ul><li>One</li><li><a href=\"https://example.com\">https://example.com</a></li></ul>",
+			want: "- One\n- https://example.com",
+		},
+		{
+			name: "styles and tracking pixels are dropped",
+			in:   "<html><head><style>p { color: red; }</style></head><body><div>Notes</div><img src=\"https://track.example.com/p.gif\"></body></html>",
+			want: "Notes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToMarkdown(tt.in); got != tt.want {
+				t.Errorf("ToMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	normalizer, err := NewNormalizer(nil, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := "<p>Quarterly planning, please review the roadmap before.</p>" +
+		"<br>-::~:~::~:~:~:~:~:~:~:~:~:~:~:~:~::~:~::-<br>Join with Google Meet: https://meet.google.com/abc-defg-hij<br>" +
+		"Join by phone: +1 555 0100 PIN: 1234<br>-::~:~::~:~:~:~:~:~:~:~:~:~:~:~:~::~:~::-"
+	if got, want := normalizer.Normalize(in), "Quarterly planning, please review the roadmap before."; got != want {
+		t.Errorf("Normalize() = %q, want %q", got, want)
+	}
+	if got, want := normalizer.Short(in), "Quarterly planning…"; got != want {
+		t.Errorf("Short() = %q, want %q", got, want)
+	}
+
+	dialIn := "Discuss budget\nMeeting ID: 123 456\nPasscode: 42\nBring numbers"
+	if got, want := normalizer.Normalize(dialIn), "Discuss budget\n\nBring numbers"; got != want {
+		t.Errorf("Normalize() = %q, want %q", got, want)
+	}
+}
+
+func TestNewNormalizerInvalidPattern(t *testing.T) {
+	if _, err := NewNormalizer([]string{"("}, 0); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestNormalizeKeepsTextAfterInvite(t *testing.T) {
+	normalizer, err := NewNormalizer(nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "google meet",
+			in: "Join with Google Meet: https://meet.google.com/abc-defg-hij\nJoin by phone\n(US) +1 555-0100 PIN: 1234\n" +
+				"Learn more about Meet at: https://support.google.com/a/users/answer/9282720\n\nAgenda: roadmap",
+			want: "Agenda: roadmap",
+		},
+		{
+			name: "google meet link only",
+			in:   "Join with Google Meet: https://meet.google.com/abc-defg-hij\n\nAgenda: roadmap",
+			want: "Agenda: roadmap",
+		},
+		{
+			name: "zoom",
+			in: "Hi all\n──────────\nJoin Zoom Meeting\nhttps://us02web.zoom.us/j/123?pwd=abc\n\nMeeting ID: 123\n" +
+				"Find your local number: https://us02web.zoom.us/u/kd\n\nPlease read the doc first",
+			want: "Hi all\n\nPlease read the doc first",
+		},
+		{
+			name: "zoom link only",
+			in:   "Join Zoom Meeting\nhttps://us02web.zoom.us/j/123\n\nPlease read the doc first",
+			want: "Please read the doc first",
+		},
+		{
+			name: "teams",
+			in: "Sync\n____________\nMicrosoft Teams meeting\nJoin on your computer\nClick here to join the meeting\n____________\n" +
+				"Notes from last week",
+			want: "Sync\n\nNotes from last week",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizer.Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
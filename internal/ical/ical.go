@@ -0,0 +1,440 @@
+// Package ical parses iCalendar (RFC 5545) data as published in .ics feeds and
+// stored in vdir collections, and expands recurring events into occurrences.
+//
+// Only the parts needed to show an agenda are supported: VEVENT components with
+// their times, recurrence rules, exceptions and overrides, attendees and the
+// calendar name and color extensions used by common calendar applications.
+// Timezones are resolved by their TZID in the IANA database, VTIMEZONE
+// definitions are not interpreted.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+// Calendar is a parsed VCALENDAR.
+type Calendar struct {
+	// Name is the X-WR-CALNAME of the calendar, if any.
+	Name string
+	// Color is the X-APPLE-CALENDAR-COLOR or COLOR of the calendar, if any.
+	Color  string
+	Events []Event
+}
+
+// Attendee is an attendee of an event.
+type Attendee struct {
+	Name  string
+	Email string
+	// PartStat is the participation status, e.g. ACCEPTED or NEEDS-ACTION.
+	PartStat string
+	// Resource is set for rooms and equipment.
+	Resource bool
+}
+
+// Event is a parsed VEVENT. Recurring events hold their recurrence rule, use
+// Occurrences to expand them.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	// Status is TENTATIVE, CONFIRMED or CANCELLED if set.
+	Status string
+	Start  time.Time
+	End    time.Time
+	// AllDay is set for events with a DATE rather than a DATE-TIME start.
+	AllDay bool
+	// TimeZone is the TZID of the start, if any.
+	TimeZone  string
+	Organizer string
+	Attendees []Attendee
+	// RRule is the recurrence rule of the event, nil for single events.
+	RRule   *RRule
+	RDates  []time.Time
+	ExDates []time.Time
+	// RecurrenceID is set for overrides of a single occurrence of a recurring event.
+	RecurrenceID time.Time
+}
+
+// property is a content line split into its name, parameters and value.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads an iCalendar stream. Times without a timezone, and the dates of
+// all-day events, are interpreted in loc. Events with invalid properties are
+// skipped with a warning, so one broken event does not hide the whole calendar.
+func Parse(r io.Reader, loc *time.Location) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &Calendar{}
+	var event *Event
+	var duration string
+	// invalid is the first error in the current event, the event is skipped at its end
+	var invalid error
+	// depth tracks nested components like VALARM inside a VEVENT, their properties are ignored
+	depth := 0
+	for i, line := range lines {
+		prop, err := parseLine(line)
+		if err != nil && event != nil {
+			if invalid == nil {
+				invalid = fmt.Errorf("line %d: %w", i+1, err)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch {
+		case prop.name == "BEGIN":
+			if event == nil && strings.EqualFold(prop.value, "VEVENT") {
+				event = &Event{}
+				duration = ""
+				invalid = nil
+			} else if event != nil {
+				depth++
+			}
+			continue
+		case prop.name == "END":
+			if event != nil && depth > 0 {
+				depth--
+			} else if event != nil && strings.EqualFold(prop.value, "VEVENT") {
+				if invalid == nil {
+					invalid = finishEvent(event, duration)
+				}
+				if invalid != nil {
+					log.Printf("Warning: skipping event %s: %v", event.UID, invalid)
+				} else {
+					calendar.Events = append(calendar.Events, *event)
+				}
+				event = nil
+			}
+			continue
+		case depth > 0:
+			continue
+		case event == nil:
+			switch prop.name {
+			case "X-WR-CALNAME":
+				calendar.Name = prop.value
+			case "X-APPLE-CALENDAR-COLOR", "COLOR":
+				calendar.Color = prop.value
+			}
+			continue
+		}
+
+		if err := event.set(prop, loc, &duration); err != nil && invalid == nil {
+			invalid = fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	if event != nil {
+		return nil, errors.New("unterminated VEVENT")
+	}
+	return calendar, nil
+}
+
+// set applies a property to the event.
+func (e *Event) set(prop property, loc *time.Location, duration *string) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SUMMARY":
+		e.Summary = unescape(prop.value)
+	case "DESCRIPTION":
+		e.Description = unescape(prop.value)
+	case "LOCATION":
+		e.Location = unescape(prop.value)
+	case "URL":
+		e.URL = prop.value
+	case "STATUS":
+		e.Status = strings.ToUpper(prop.value)
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseTime(prop, loc)
+		e.TimeZone = prop.params["TZID"]
+	case "DTEND":
+		e.End, _, err = parseTime(prop, loc)
+	case "DURATION":
+		*duration = prop.value
+	case "RRULE":
+		e.RRule, err = ParseRRule(prop.value)
+	case "RDATE":
+		var dates []time.Time
+		dates, err = parseTimeList(prop, loc)
+		e.RDates = append(e.RDates, dates...)
+	case "EXDATE":
+		var dates []time.Time
+		dates, err = parseTimeList(prop, loc)
+		e.ExDates = append(e.ExDates, dates...)
+	case "RECURRENCE-ID":
+		e.RecurrenceID, _, err = parseTime(prop, loc)
+	case "ORGANIZER":
+		e.Organizer = attendeeName(prop)
+	case "ATTENDEE":
+		e.Attendees = append(e.Attendees, Attendee{
+			Name:     prop.params["CN"],
+			Email:    mailAddress(prop.value),
+			PartStat: strings.ToUpper(prop.params["PARTSTAT"]),
+			Resource: strings.EqualFold(prop.params["CUTYPE"], "RESOURCE") || strings.EqualFold(prop.params["CUTYPE"], "ROOM"),
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", prop.name, prop.value, err)
+	}
+	return nil
+}
+
+// finishEvent derives the end of the event if it was not given explicitly.
+func finishEvent(e *Event, duration string) error {
+	if e.Start.IsZero() {
+		return errors.New("missing DTSTART")
+	}
+	if !e.End.IsZero() {
+		return nil
+	}
+	switch {
+	case duration != "":
+		d, err := parseDuration(duration)
+		if err != nil {
+			return fmt.Errorf("invalid DURATION %q: %w", duration, err)
+		}
+		e.End = addDuration(e.Start, d)
+	case e.AllDay:
+		// An all-day event without an end lasts one day
+		e.End = e.Start.AddDate(0, 0, 1)
+	default:
+		e.End = e.Start
+	}
+	return nil
+}
+
+// unfold reads the content lines of the stream, joining folded lines.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseLine splits a content line like DTSTART;TZID=Europe/Berlin:20250310T090000.
+func parseLine(line string) (property, error) {
+	prop := property{params: make(map[string]string)}
+
+	// The name and parameters end at the first colon outside of a quoted parameter value
+	end := -1
+	quoted := false
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return prop, fmt.Errorf("invalid content line %q", line)
+	}
+	prop.value = line[end+1:]
+
+	parts := splitQuoted(line[:end], ';')
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// splitQuoted splits s at every separator outside of double quotes.
+func splitQuoted(s string, separator rune) []string {
+	var parts []string
+	start := 0
+	quoted := false
+	for i, r := range s {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == separator && !quoted {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescape decodes the escaped characters of a TEXT value.
+func unescape(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// parseTime parses a DATE or DATE-TIME value and reports whether it is a date.
+func parseTime(prop property, loc *time.Location) (time.Time, bool, error) {
+	return parseTimeValue(prop.value, prop.params, loc)
+}
+
+// parseTimeList parses a comma separated list of DATE or DATE-TIME values.
+func parseTimeList(prop property, loc *time.Location) ([]time.Time, error) {
+	var times []time.Time
+	for _, value := range strings.Split(prop.value, ",") {
+		t, _, err := parseTimeValue(value, prop.params, loc)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+// parseTimeValue parses a single DATE or DATE-TIME value. UTC times end in Z, other
+// times are in the timezone of their TZID parameter or floating in loc.
+func parseTimeValue(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	if tzid := params["TZID"]; tzid != "" {
+		loc = lookupLocation(tzid, loc)
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// lookupLocation resolves a TZID. Some applications prefix IANA names with a path or
+// use Windows names, unknown zones fall back to the given location.
+func lookupLocation(tzid string, fallback *time.Location) *time.Location {
+	tzid = strings.TrimPrefix(tzid, "/")
+	if zone, err := LoadLocation(tzid); err == nil {
+		return zone
+	}
+	// e.g. /mozilla.org/20050126_1/Europe/Berlin
+	parts := strings.Split(tzid, "/")
+	for i := range parts {
+		if zone, err := time.LoadLocation(strings.Join(parts[i:], "/")); err == nil {
+			return zone
+		}
+	}
+	return fallback
+}
+
+// LoadLocation loads a timezone by its IANA name or by one of the common Windows names
+// used by Outlook and Exchange, e.g. "W. Europe Standard Time". The name of the returned
+// location is always the IANA name.
+func LoadLocation(name string) (*time.Location, error) {
+	if ianaName, ok := windowsZones[name]; ok {
+		name = ianaName
+	}
+	return time.LoadLocation(name)
+}
+
+// windowsZones maps the most common Windows timezone names used by Outlook to IANA names.
+var windowsZones = map[string]string{
+	"UTC":                             "UTC",
+	"GMT Standard Time":               "Europe/London",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"Romance Standard Time":           "Europe/Paris",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"FLE Standard Time":               "Europe/Kiev",
+	"Eastern Standard Time":           "America/New_York",
+	"Central Standard Time":           "America/Chicago",
+	"Mountain Standard Time":          "America/Denver",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"India Standard Time":             "Asia/Kolkata",
+	"China Standard Time":             "Asia/Shanghai",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"Israel Standard Time":            "Asia/Jerusalem",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+	"Atlantic Standard Time":          "America/Halifax",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Central America Standard Time":   "America/Guatemala",
+	"SA Pacific Standard Time":        "America/Bogota",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"Russian Standard Time":           "Europe/Moscow",
+	"Korea Standard Time":             "Asia/Seoul",
+	"Taipei Standard Time":            "Asia/Taipei",
+	"SE Asia Standard Time":           "Asia/Bangkok",
+	"Turkey Standard Time":            "Europe/Istanbul",
+	"Egypt Standard Time":             "Africa/Cairo",
+	"Argentina Standard Time":         "America/Argentina/Buenos_Aires",
+	"Newfoundland Standard Time":      "America/St_Johns",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"Pacific SA Standard Time":        "America/Santiago",
+	"Cen. Australia Standard Time":    "Australia/Adelaide",
+	"W. Australia Standard Time":      "Australia/Perth",
+	"E. Australia Standard Time":      "Australia/Brisbane",
+	"Pakistan Standard Time":          "Asia/Karachi",
+	"Bangladesh Standard Time":        "Asia/Dhaka",
+	"Nepal Standard Time":             "Asia/Kathmandu",
+	"Iran Standard Time":              "Asia/Tehran",
+	"Arab Standard Time":              "Asia/Riyadh",
+	"W. Central Africa Standard Time": "Africa/Lagos",
+	"Mexico Standard Time":            "America/Mexico_City",
+	"Central Standard Time (Mexico)":  "America/Mexico_City",
+	"Canada Central Standard Time":    "America/Regina",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+}
+
+// attendeeName returns the common name of an organizer or attendee, or their email address.
+func attendeeName(prop property) string {
+	if name := prop.params["CN"]; name != "" {
+		return name
+	}
+	return mailAddress(prop.value)
+}
+
+// mailAddress strips the mailto: scheme of a calendar user address.
+func mailAddress(value string) string {
+	if len(value) >= len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		return value[len("mailto:"):]
+	}
+	return value
+}
@@ -0,0 +1,203 @@
+package ical
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Example//EN
+X-WR-CALNAME:Team
+X-APPLE-CALENDAR-COLOR:#3b82f6
+BEGIN:VTIMEZONE
+TZID:Europe/Berlin
+BEGIN:STANDARD
+DTSTART:19701025T030000
+TZOFFSETFROM:+0200
+TZOFFSETTO:+0100
+END:STANDARD
+END:VTIMEZONE
+BEGIN:VEVENT
+UID:standup@example.com
+SUMMARY:Standup\, daily
+DESCRIPTION:Agenda:\n- updates\n- blockers with a long line that is folded
+  onto the next line
+LOCATION:Room 1
+DTSTART;TZID=Europe/Berlin:20250310T090000
+DTEND;TZID=Europe/Berlin:20250310T093000
+STATUS:CONFIRMED
+ORGANIZER;CN=Jane Doe:mailto:jane@example.com
+ATTENDEE;CN="Doe, John";PARTSTAT=ACCEPTED:mailto:john@example.com
+ATTENDEE;CUTYPE=RESOURCE;CN=Room 1:mailto:room1@example.com
+BEGIN:VALARM
+ACTION:DISPLAY
+DESCRIPTION:Reminder
+TRIGGER:-PT10M
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:holiday@example.com
+SUMMARY:Holiday
+DTSTART;VALUE=DATE:20250310
+END:VEVENT
+BEGIN:VEVENT
+UID:review@example.com
+SUMMARY:Review
+DTSTART:20250310T130000Z
+DURATION:PT1H30M
+END:VEVENT
+END:VCALENDAR
+`
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestParse(t *testing.T) {
+	berlin := mustLocation(t, "Europe/Berlin")
+	calendar, err := Parse(strings.NewReader(strings.ReplaceAll(sampleCalendar, "\n", "\r\n")), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if calendar.Name != "Team" || calendar.Color != "#3b82f6" {
+		t.Errorf("calendar = %q %q", calendar.Name, calendar.Color)
+	}
+	if len(calendar.Events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(calendar.Events))
+	}
+
+	standup := calendar.Events[0]
+	if standup.Summary != "Standup, daily" {
+		t.Errorf("summary = %q", standup.Summary)
+	}
+	if want := "Agenda:\n- updates\n- blockers with a long line that is folded onto the next line"; standup.Description != want {
+		t.Errorf("description = %q, want %q", standup.Description, want)
+	}
+	if !standup.Start.Equal(time.Date(2025, 3, 10, 9, 0, 0, 0, berlin)) || standup.End.Sub(standup.Start) != 30*time.Minute {
+		t.Errorf("standup = %s-%s", standup.Start, standup.End)
+	}
+	if standup.TimeZone != "Europe/Berlin" || standup.Status != "CONFIRMED" || standup.Organizer != "Jane Doe" {
+		t.Errorf("standup timezone = %q, status = %q, organizer = %q", standup.TimeZone, standup.Status, standup.Organizer)
+	}
+	if len(standup.Attendees) != 2 || standup.Attendees[0].Name != "Doe, John" || standup.Attendees[0].PartStat != "ACCEPTED" ||
+		standup.Attendees[0].Email != "john@example.com" || !standup.Attendees[1].Resource {
+		t.Errorf("attendees = %+v", standup.Attendees)
+	}
+
+	holiday := calendar.Events[1]
+	if !holiday.AllDay || !holiday.Start.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)) || !holiday.End.Equal(holiday.Start.AddDate(0, 0, 1)) {
+		t.Errorf("holiday = %+v", holiday)
+	}
+
+	review := calendar.Events[2]
+	if !review.Start.Equal(time.Date(2025, 3, 10, 13, 0, 0, 0, time.UTC)) || review.End.Sub(review.Start) != 90*time.Minute {
+		t.Errorf("review = %s-%s", review.Start, review.End)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"unterminated": "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20250310T090000Z\n",
+		"bad line":     "BEGIN:VCALENDAR\nX-WR-CALNAME\nEND:VCALENDAR\n",
+	}
+	for name, data := range tests {
+		if _, err := Parse(strings.NewReader(data), time.UTC); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParseSkipsInvalidEvents(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT", "UID:no-start", "SUMMARY:x", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:bad-time", "DTSTART:2025-03-10", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:bad-rule", "DTSTART:20250310T090000Z", "RRULE:FREQ=SOMETIMES", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:bad-line", "DTSTART:20250310T090000Z", "SUMMARY", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:standup", "DTSTART:20250310T090000Z", "DURATION:PT15M", "END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")
+	calendar, err := Parse(strings.NewReader(data), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(calendar.Events) != 1 || calendar.Events[0].UID != "standup" {
+		t.Fatalf("Parse() events = %+v, want only standup", calendar.Events)
+	}
+	for _, uid := range []string{"no-start", "bad-time", "bad-rule", "bad-line"} {
+		if !strings.Contains(logs.String(), "skipping event "+uid) {
+			t.Errorf("no warning about %s in %q", uid, logs.String())
+		}
+	}
+}
+
+func TestLookupLocation(t *testing.T) {
+	berlin := mustLocation(t, "Europe/Berlin")
+	for _, tzid := range []string{"Europe/Berlin", "/mozilla.org/20050126_1/Europe/Berlin", "W. Europe Standard Time"} {
+		if loc := lookupLocation(tzid, time.UTC); loc.String() != berlin.String() {
+			t.Errorf("lookupLocation(%q) = %s, want %s", tzid, loc, berlin)
+		}
+	}
+	if loc := lookupLocation("Nowhere/Special", time.UTC); loc != time.UTC {
+		t.Errorf("lookupLocation of an unknown zone = %s, want the fallback", loc)
+	}
+}
+
+func TestLoadLocation(t *testing.T) {
+	mustLocation(t, "Europe/Berlin")
+	for name, want := range map[string]string{
+		"W. Europe Standard Time": "Europe/Berlin",
+		"Pacific Standard Time":   "America/Los_Angeles",
+		"Asia/Tokyo":              "Asia/Tokyo",
+		"UTC":                     "UTC",
+	} {
+		loc, err := LoadLocation(name)
+		if err != nil {
+			t.Errorf("LoadLocation(%q) error = %v", name, err)
+			continue
+		}
+		if loc.String() != want {
+			t.Errorf("LoadLocation(%q) = %s, want %s", name, loc, want)
+		}
+	}
+	if _, err := LoadLocation("Nowhere Standard Time"); err == nil {
+		t.Error("LoadLocation of an unknown zone should fail")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]duration{
+		"PT1H30M": {clock: 90 * time.Minute},
+		"P1D":     {days: 1},
+		"P1W":     {days: 7},
+		"-PT15M":  {clock: -15 * time.Minute},
+		"P1DT2H":  {days: 1, clock: 2 * time.Hour},
+		"PT10S":   {clock: 10 * time.Second},
+	}
+	for input, want := range tests {
+		got, err := parseDuration(input)
+		if err != nil || got != want {
+			t.Errorf("parseDuration(%q) = %+v, %v, want %+v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "P", "PT", "1H", "P1H"} {
+		if _, err := parseDuration(input); err == nil {
+			t.Errorf("parseDuration(%q) should fail", input)
+		}
+	}
+}
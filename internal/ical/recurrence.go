@@ -0,0 +1,577 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPeriods bounds the number of periods a rule is expanded for. Rules without a COUNT start
+// at the period of the window, so only rules with a COUNT far before the window reach it.
+const maxPeriods = 100000
+
+// Recurrence frequencies.
+const (
+	Secondly = "SECONDLY"
+	Minutely = "MINUTELY"
+	Hourly   = "HOURLY"
+	Daily    = "DAILY"
+	Weekly   = "WEEKLY"
+	Monthly  = "MONTHLY"
+	Yearly   = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry like MO, 2TU or -1FR. N is 0 for every such day of the period.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// RRule is a recurrence rule. BYHOUR, BYMINUTE, BYSECOND, BYWEEKNO and BYYEARDAY are not supported.
+type RRule struct {
+	Freq     string
+	Interval int
+	// Count limits the number of occurrences if it is not 0.
+	Count int
+	// Until is the raw UNTIL value, it is resolved in the timezone of the event start.
+	Until      string
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+// weekdayCodes maps the two letter weekday codes of iCalendar.
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// durationPattern matches iCalendar durations like PT1H30M, P1D or -P1W.
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// duration is an iCalendar duration. Days are kept apart from the time, so adding
+// a day keeps the time of day across daylight saving time changes.
+type duration struct {
+	days  int
+	clock time.Duration
+}
+
+// ParseRRule parses the value of an RRULE property.
+func ParseRRule(value string) (*RRule, error) {
+	rule := &RRule{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(value, ";") {
+		key, val, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+			if err == nil && rule.Interval < 1 {
+				err = errors.New("interval must be positive")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			rule.Until = val
+		case "BYDAY":
+			rule.ByDay, err = parseWeekdayNums(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseInts(val)
+		case "BYMONTH":
+			var months []int
+			months, err = parseInts(val)
+			for _, month := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		case "BYSETPOS":
+			rule.BySetPos, err = parseInts(val)
+		case "WKST":
+			weekday, ok := weekdayCodes[strings.ToUpper(val)]
+			if !ok {
+				err = fmt.Errorf("unknown weekday %q", val)
+			}
+			rule.WeekStart = weekday
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+
+	switch rule.Freq {
+	case Secondly, Minutely, Hourly, Daily, Weekly, Monthly, Yearly:
+		return rule, nil
+	case "":
+		return nil, errors.New("missing FREQ")
+	default:
+		return nil, fmt.Errorf("unknown FREQ %q", rule.Freq)
+	}
+}
+
+// parseWeekdayNums parses a comma separated BYDAY list.
+func parseWeekdayNums(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.ToUpper(strings.TrimSpace(entry))
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", entry)
+		}
+		weekday, ok := weekdayCodes[entry[len(entry)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", entry)
+		}
+		n := 0
+		if prefix := entry[:len(entry)-2]; prefix != "" {
+			var err error
+			if n, err = strconv.Atoi(prefix); err != nil {
+				return nil, fmt.Errorf("invalid weekday %q", entry)
+			}
+		}
+		days = append(days, WeekdayNum{Weekday: weekday, N: n})
+	}
+	return days, nil
+}
+
+// parseInts parses a comma separated list of integers.
+func parseInts(value string) ([]int, error) {
+	var numbers []int
+	for _, entry := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, nil
+}
+
+// parseDuration parses the value of a DURATION property.
+func parseDuration(value string) (duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.ToUpper(value))
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return duration{}, fmt.Errorf("invalid duration %q", value)
+	}
+	number := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+
+	d := duration{
+		days: number(match[2])*7 + number(match[3]),
+		clock: time.Duration(number(match[4]))*time.Hour +
+			time.Duration(number(match[5]))*time.Minute +
+			time.Duration(number(match[6]))*time.Second,
+	}
+	if match[1] == "-" {
+		d.days, d.clock = -d.days, -d.clock
+	}
+	return d, nil
+}
+
+// addDuration adds an iCalendar duration to t.
+func addDuration(t time.Time, d duration) time.Time {
+	return t.AddDate(0, 0, d.days).Add(d.clock)
+}
+
+// until resolves the UNTIL of the rule in the location of the event start.
+func (r *RRule) until(loc *time.Location) (time.Time, error) {
+	if r.Until == "" {
+		return time.Time{}, nil
+	}
+	t, isDate, err := parseTimeValue(r.Until, nil, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid UNTIL %q: %w", r.Until, err)
+	}
+	if isDate {
+		// A date includes occurrences on that whole day
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+// Expand calls fn with the start of each occurrence of the rule, in order, beginning with
+// dtstart itself. Unless the rule has a COUNT, which needs every occurrence to be counted,
+// the occurrences of periods before the one containing from are skipped. It stops when the
+// rule ends, an occurrence starts at or after end, or fn returns false.
+func (r *RRule) Expand(dtstart, from, end time.Time, fn func(time.Time) bool) error {
+	until, err := r.until(dtstart.Location())
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	emit := func(t time.Time) bool {
+		if (r.Count > 0 && count >= r.Count) || (!until.IsZero() && t.After(until)) || !t.Before(end) {
+			return false
+		}
+		count++
+		return fn(t)
+	}
+
+	if !emit(dtstart) {
+		return nil
+	}
+	first := r.firstPeriod(dtstart, from)
+	for n := first; n < first+maxPeriods; n++ {
+		// Periods start in order, so once a period starts at the end nothing follows
+		if !r.periodStart(dtstart, n).Before(end) {
+			return nil
+		}
+		for _, candidate := range r.period(dtstart, n) {
+			if !candidate.After(dtstart) {
+				continue
+			}
+			if !emit(candidate) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("stopped expanding the rule after %d periods", maxPeriods)
+}
+
+// firstPeriod returns the number of the period of the rule containing from, or 0 if the rule has a COUNT.
+func (r *RRule) firstPeriod(dtstart, from time.Time) int {
+	if r.Count > 0 || !from.After(dtstart) {
+		return 0
+	}
+	from = from.In(dtstart.Location())
+	var periods int
+	switch r.Freq {
+	case Secondly:
+		periods = int(from.Sub(dtstart) / time.Second)
+	case Minutely:
+		periods = int(from.Sub(dtstart) / time.Minute)
+	case Hourly:
+		periods = int(from.Sub(dtstart) / time.Hour)
+	case Daily:
+		periods = daysBetween(dtstart, from)
+	case Weekly:
+		periods = daysBetween(r.periodStart(dtstart, 0), from) / 7
+	case Monthly:
+		periods = (from.Year()-dtstart.Year())*12 + int(from.Month()-dtstart.Month())
+	case Yearly:
+		periods = from.Year() - dtstart.Year()
+	}
+	return periods / r.Interval
+}
+
+// periodStart returns a time at or before every occurrence of the nth period of the rule after dtstart.
+func (r *RRule) periodStart(dtstart time.Time, n int) time.Time {
+	step := n * r.Interval
+	switch r.Freq {
+	case Secondly:
+		return dtstart.Add(time.Duration(step) * time.Second)
+	case Minutely:
+		return dtstart.Add(time.Duration(step) * time.Minute)
+	case Hourly:
+		return dtstart.Add(time.Duration(step) * time.Hour)
+	case Daily:
+		return dtstart.AddDate(0, 0, step)
+	case Weekly:
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		return dtstart.AddDate(0, 0, step*7-offset)
+	case Monthly:
+		return time.Date(dtstart.Year(), dtstart.Month()+time.Month(step), 1, 0, 0, 0, 0, dtstart.Location())
+	default:
+		return time.Date(dtstart.Year()+step, time.January, 1, 0, 0, 0, 0, dtstart.Location())
+	}
+}
+
+// period returns the sorted occurrences of the nth period of the rule after dtstart.
+func (r *RRule) period(dtstart time.Time, n int) []time.Time {
+	step := n * r.Interval
+	var candidates []time.Time
+	switch r.Freq {
+	case Secondly, Minutely, Hourly, Daily:
+		candidates = r.filter([]time.Time{r.periodStart(dtstart, n)})
+	case Weekly:
+		candidates = r.weekly(dtstart, n)
+	case Monthly:
+		month := time.Date(dtstart.Year(), dtstart.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		if len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, month.Month()) {
+			candidates = r.monthDays(dtstart, month.Year(), month.Month())
+		}
+	case Yearly:
+		candidates = r.yearly(dtstart, dtstart.Year()+step)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	candidates = slices.CompactFunc(candidates, func(a, b time.Time) bool { return a.Equal(b) })
+	return r.setPos(candidates)
+}
+
+// filter keeps the times matching BYMONTH, BYMONTHDAY and BYDAY, used by frequencies of a day or less.
+func (r *RRule) filter(times []time.Time) []time.Time {
+	var kept []time.Time
+	for _, t := range times {
+		if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, t.Month()) {
+			continue
+		}
+		if len(r.ByMonthDay) > 0 && !slices.Contains(resolveMonthDays(r.ByMonthDay, t.Year(), t.Month()), t.Day()) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.hasWeekday(t.Weekday()) {
+			continue
+		}
+		kept = append(kept, t)
+	}
+	return kept
+}
+
+// weekly returns the occurrences in the week of the nth period after dtstart.
+func (r *RRule) weekly(dtstart time.Time, n int) []time.Time {
+	weekStart := r.periodStart(dtstart, n)
+
+	weekdays := []time.Weekday{dtstart.Weekday()}
+	if len(r.ByDay) > 0 {
+		weekdays = weekdays[:0]
+		for _, day := range r.ByDay {
+			weekdays = append(weekdays, day.Weekday)
+		}
+	}
+
+	var candidates []time.Time
+	for _, weekday := range weekdays {
+		day := weekStart.AddDate(0, 0, (int(weekday)-int(r.WeekStart)+7)%7)
+		if len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, day.Month()) {
+			candidates = append(candidates, day)
+		}
+	}
+	return candidates
+}
+
+// yearly returns the occurrences in the given year.
+func (r *RRule) yearly(dtstart time.Time, year int) []time.Time {
+	switch {
+	case len(r.ByMonth) > 0:
+		var candidates []time.Time
+		for _, month := range r.ByMonth {
+			candidates = append(candidates, r.monthDays(dtstart, year, month)...)
+		}
+		return candidates
+	case len(r.ByMonthDay) > 0:
+		var candidates []time.Time
+		for month := time.January; month <= time.December; month++ {
+			candidates = append(candidates, r.monthDays(dtstart, year, month)...)
+		}
+		return candidates
+	case len(r.ByDay) > 0:
+		// Without BYMONTH the ordinals of BYDAY count within the whole year
+		var days []int
+		daysInYear := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Weekday()
+		for _, day := range r.ByDay {
+			days = append(days, nthWeekdays(day, first, daysInYear)...)
+		}
+		var candidates []time.Time
+		for _, yearDay := range days {
+			candidates = append(candidates, at(dtstart, year, time.January, yearDay))
+		}
+		return candidates
+	default:
+		if dtstart.Day() > daysIn(year, dtstart.Month()) {
+			return nil
+		}
+		return []time.Time{at(dtstart, year, dtstart.Month(), dtstart.Day())}
+	}
+}
+
+// monthDays returns the occurrences in the given month, defaulting to the day of the month of dtstart.
+func (r *RRule) monthDays(dtstart time.Time, year int, month time.Month) []time.Time {
+	var days []int
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, day := range resolveMonthDays(r.ByMonthDay, year, month) {
+			weekday := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday()
+			if len(r.ByDay) == 0 || r.hasWeekday(weekday) {
+				days = append(days, day)
+			}
+		}
+	case len(r.ByDay) > 0:
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+		for _, day := range r.ByDay {
+			days = append(days, nthWeekdays(day, first, daysIn(year, month))...)
+		}
+	case dtstart.Day() <= daysIn(year, month):
+		days = []int{dtstart.Day()}
+	}
+
+	candidates := make([]time.Time, 0, len(days))
+	for _, day := range days {
+		candidates = append(candidates, at(dtstart, year, month, day))
+	}
+	return candidates
+}
+
+// setPos applies BYSETPOS to the sorted occurrences of a period.
+func (r *RRule) setPos(candidates []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return candidates
+	}
+	var selected []time.Time
+	for _, pos := range r.BySetPos {
+		index := pos - 1
+		if pos < 0 {
+			index = len(candidates) + pos
+		}
+		if index >= 0 && index < len(candidates) {
+			selected = append(selected, candidates[index])
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Before(selected[j]) })
+	return selected
+}
+
+// hasWeekday reports whether BYDAY contains the weekday, ignoring ordinals.
+func (r *RRule) hasWeekday(weekday time.Weekday) bool {
+	return slices.ContainsFunc(r.ByDay, func(day WeekdayNum) bool { return day.Weekday == weekday })
+}
+
+// nthWeekdays returns the 1-based days of a period of the given length starting on the weekday first,
+// that match a BYDAY entry. Entries without an ordinal match every such day.
+func nthWeekdays(day WeekdayNum, first time.Weekday, length int) []int {
+	var matches []int
+	for d := 1 + (int(day.Weekday)-int(first)+7)%7; d <= length; d += 7 {
+		matches = append(matches, d)
+	}
+	switch {
+	case day.N == 0:
+		return matches
+	case day.N > 0 && day.N <= len(matches):
+		return []int{matches[day.N-1]}
+	case day.N < 0 && -day.N <= len(matches):
+		return []int{matches[len(matches)+day.N]}
+	default:
+		return nil
+	}
+}
+
+// resolveMonthDays converts BYMONTHDAY values, negative ones count from the end of the month,
+// to the days that exist in the month.
+func resolveMonthDays(monthDays []int, year int, month time.Month) []int {
+	length := daysIn(year, month)
+	var days []int
+	for _, day := range monthDays {
+		if day < 0 {
+			day = length + day + 1
+		}
+		if day >= 1 && day <= length {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// daysBetween returns the number of calendar days from the day of a to the day of b.
+func daysBetween(a, b time.Time) int {
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(dayB.Sub(dayA) / (24 * time.Hour))
+}
+
+// daysIn returns the number of days of the month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// at returns the day of the month with the time of day and location of dtstart. Days past the end
+// of the month roll over, which is used to address days of the year.
+func at(dtstart time.Time, year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+}
+
+// Occurrences returns the events of the calendar that overlap [start, end), sorted by start.
+// Recurring events are expanded into one event per occurrence without their rule, excluding
+// EXDATEs and occurrences that are overridden by an event with a RECURRENCE-ID.
+func (c *Calendar) Occurrences(start, end time.Time) ([]Event, error) {
+	// overridden holds the original starts of occurrences replaced by an override, per UID
+	overridden := make(map[string][]time.Time)
+	for _, event := range c.Events {
+		if !event.RecurrenceID.IsZero() {
+			overridden[event.UID] = append(overridden[event.UID], event.RecurrenceID)
+		}
+	}
+
+	var occurrences []Event
+	add := func(event Event) {
+		if overlaps(event, start, end) {
+			occurrences = append(occurrences, event)
+		}
+	}
+
+	for _, event := range c.Events {
+		if !event.RecurrenceID.IsZero() || (event.RRule == nil && len(event.RDates) == 0) {
+			add(event)
+			continue
+		}
+
+		excluded := append(slices.Clone(event.ExDates), overridden[event.UID]...)
+		instance := func(t time.Time) {
+			if slices.ContainsFunc(excluded, t.Equal) {
+				return
+			}
+			occurrence := event
+			occurrence.RRule, occurrence.RDates, occurrence.ExDates = nil, nil, nil
+			occurrence.Start = t
+			occurrence.End = occurrenceEnd(event, t)
+			add(occurrence)
+		}
+
+		if event.RRule == nil {
+			instance(event.Start)
+		} else {
+			// Occurrences starting up to the duration of the event before start still overlap
+			from := start.Add(-event.End.Sub(event.Start))
+			err := event.RRule.Expand(event.Start, from, end, func(t time.Time) bool {
+				instance(t)
+				return true
+			})
+			if err != nil {
+				return nil, fmt.Errorf("event %s: %w", event.UID, err)
+			}
+		}
+		for _, rdate := range event.RDates {
+			if !rdate.Equal(event.Start) {
+				instance(rdate)
+			}
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Start.Before(occurrences[j].Start)
+	})
+	return occurrences, nil
+}
+
+// occurrenceEnd returns the end of the occurrence of a recurring event starting at t.
+// All-day events keep their number of days, others their duration.
+func occurrenceEnd(event Event, t time.Time) time.Time {
+	if event.AllDay {
+		days := int(event.End.Sub(event.Start).Round(24*time.Hour) / (24 * time.Hour))
+		return t.AddDate(0, 0, days)
+	}
+	return t.Add(event.End.Sub(event.Start))
+}
+
+// overlaps reports whether the event overlaps [start, end). Events without a duration
+// overlap if they start in the range.
+func overlaps(event Event, start, end time.Time) bool {
+	if !event.Start.Before(end) {
+		return false
+	}
+	if event.End.After(event.Start) {
+		return event.End.After(start)
+	}
+	return !event.Start.Before(start)
+}
@@ -0,0 +1,288 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// expand returns the occurrences of the rule starting at dtstart before end, formatted for comparison.
+func expand(t *testing.T, rule string, dtstart, end time.Time) []string {
+	t.Helper()
+	return expandFrom(t, rule, dtstart, dtstart, end)
+}
+
+// expandFrom is expand, skipping the periods before the one containing from.
+func expandFrom(t *testing.T, rule string, dtstart, from, end time.Time) []string {
+	t.Helper()
+	rrule, err := ParseRRule(rule)
+	if err != nil {
+		t.Fatalf("ParseRRule(%q) error = %v", rule, err)
+	}
+	var starts []string
+	err = rrule.Expand(dtstart, from, end, func(start time.Time) bool {
+		starts = append(starts, start.Format("2006-01-02 15:04 Mon"))
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Expand(%q) error = %v", rule, err)
+	}
+	return starts
+}
+
+func TestExpand(t *testing.T) {
+	// Monday 2025-03-10 09:00
+	dtstart := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	farAway := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule  string
+		start time.Time
+		end   time.Time
+		want  []string
+	}{
+		{
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: dtstart, end: farAway,
+			want: []string{"2025-03-10 09:00 Mon", "2025-03-11 09:00 Tue", "2025-03-12 09:00 Wed"},
+		},
+		{
+			rule:  "FREQ=DAILY;INTERVAL=2;UNTIL=20250314T090000Z",
+			start: dtstart, end: farAway,
+			want: []string{"2025-03-10 09:00 Mon", "2025-03-12 09:00 Wed", "2025-03-14 09:00 Fri"},
+		},
+		{
+			rule:  "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			start: time.Date(2025, 3, 13, 9, 0, 0, 0, time.UTC), end: time.Date(2025, 3, 18, 0, 0, 0, 0, time.UTC),
+			want: []string{"2025-03-13 09:00 Thu", "2025-03-14 09:00 Fri", "2025-03-17 09:00 Mon"},
+		},
+		{
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=5",
+			start: dtstart, end: farAway,
+			want: []string{"2025-03-10 09:00 Mon", "2025-03-12 09:00 Wed", "2025-03-17 09:00 Mon", "2025-03-19 09:00 Wed", "2025-03-24 09:00 Mon"},
+		},
+		{
+			rule:  "FREQ=WEEKLY;INTERVAL=2",
+			start: dtstart, end: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			want: []string{"2025-03-10 09:00 Mon", "2025-03-24 09:00 Mon"},
+		},
+		{
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			start: time.Date(2025, 1, 31, 16, 0, 0, 0, time.UTC), end: farAway,
+			want: []string{"2025-01-31 16:00 Fri", "2025-02-28 16:00 Fri", "2025-03-28 16:00 Fri"},
+		},
+		{
+			rule:  "FREQ=MONTHLY;BYDAY=2TU;COUNT=2",
+			start: time.Date(2025, 3, 11, 10, 0, 0, 0, time.UTC), end: farAway,
+			want: []string{"2025-03-11 10:00 Tue", "2025-04-08 10:00 Tue"},
+		},
+		{
+			// Months without a 31st are skipped
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			start: time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), end: farAway,
+			want: []string{"2025-01-31 09:00 Fri", "2025-03-31 09:00 Mon", "2025-05-31 09:00 Sat"},
+		},
+		{
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			start: time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), end: farAway,
+			want: []string{"2025-01-31 09:00 Fri", "2025-02-28 09:00 Fri", "2025-03-31 09:00 Mon"},
+		},
+		{
+			// The last workday of the month
+			rule:  "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=3",
+			start: time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), end: farAway,
+			want: []string{"2025-01-31 09:00 Fri", "2025-02-28 09:00 Fri", "2025-03-31 09:00 Mon"},
+		},
+		{
+			rule:  "FREQ=YEARLY;COUNT=3",
+			start: time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), end: farAway,
+			want: []string{"2024-02-29 09:00 Thu", "2028-02-29 09:00 Tue"},
+		},
+		{
+			// Thanksgiving
+			rule:  "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;COUNT=2",
+			start: time.Date(2025, 11, 27, 0, 0, 0, 0, time.UTC), end: farAway,
+			want: []string{"2025-11-27 00:00 Thu", "2026-11-26 00:00 Thu"},
+		},
+		{
+			rule:  "FREQ=YEARLY;BYDAY=1MO;COUNT=2",
+			start: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC), end: farAway,
+			want: []string{"2025-01-06 09:00 Mon", "2026-01-05 09:00 Mon"},
+		},
+		{
+			rule:  "FREQ=HOURLY;INTERVAL=4;COUNT=3",
+			start: dtstart, end: farAway,
+			want: []string{"2025-03-10 09:00 Mon", "2025-03-10 13:00 Mon", "2025-03-10 17:00 Mon"},
+		},
+		{
+			rule:  "FREQ=DAILY",
+			start: dtstart, end: time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC),
+			want: []string{"2025-03-10 09:00 Mon", "2025-03-11 09:00 Tue"},
+		},
+	}
+
+	for _, tt := range tests {
+		got := expand(t, tt.rule, tt.start, tt.end)
+		if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("%s:\n got %v\nwant %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestExpandKeepsWallClockAcrossDST(t *testing.T) {
+	berlin := mustLocation(t, "Europe/Berlin")
+	// Daylight saving time starts on 2025-03-30 in Berlin
+	got := expand(t, "FREQ=WEEKLY;COUNT=2", time.Date(2025, 3, 24, 9, 0, 0, 0, berlin), time.Date(2026, 1, 1, 0, 0, 0, 0, berlin))
+	want := []string{"2025-03-24 09:00 Mon", "2025-03-31 09:00 Mon"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestExpandSkipsToFrom(t *testing.T) {
+	// Monday 2025-03-10 09:00, the window is months and far more than maxPeriods minutes later
+	dtstart := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 9, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule string
+		end  time.Time
+		want []string
+	}{
+		{
+			rule: "FREQ=MINUTELY;INTERVAL=15",
+			end:  from.Add(time.Hour),
+			want: []string{"2025-03-10 09:00 Mon", "2025-09-03 12:00 Wed", "2025-09-03 12:15 Wed", "2025-09-03 12:30 Wed", "2025-09-03 12:45 Wed"},
+		},
+		{
+			rule: "FREQ=SECONDLY;INTERVAL=1800;BYDAY=WE",
+			end:  from.Add(time.Hour),
+			want: []string{"2025-03-10 09:00 Mon", "2025-09-03 12:00 Wed", "2025-09-03 12:30 Wed"},
+		},
+		{
+			rule: "FREQ=DAILY;INTERVAL=3",
+			end:  from.AddDate(0, 0, 4),
+			want: []string{"2025-03-10 09:00 Mon", "2025-09-03 09:00 Wed", "2025-09-06 09:00 Sat"},
+		},
+		{
+			rule: "FREQ=WEEKLY;BYDAY=MO,TH",
+			end:  from.AddDate(0, 0, 7),
+			want: []string{"2025-03-10 09:00 Mon", "2025-09-01 09:00 Mon", "2025-09-04 09:00 Thu", "2025-09-08 09:00 Mon"},
+		},
+		{
+			rule: "FREQ=MONTHLY;BYDAY=1MO",
+			end:  from.AddDate(0, 1, 4),
+			want: []string{"2025-03-10 09:00 Mon", "2025-09-01 09:00 Mon", "2025-10-06 09:00 Mon"},
+		},
+		{
+			// The 30th of February never occurs
+			rule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			end:  from.AddDate(1, 0, 0),
+			want: []string{"2025-03-10 09:00 Mon"},
+		},
+	}
+
+	for _, tt := range tests {
+		got := expandFrom(t, tt.rule, dtstart, from, tt.end)
+		if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("%s:\n got %v\nwant %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestExpandTooManyPeriods(t *testing.T) {
+	// A COUNT needs every occurrence since dtstart, which are more than maxPeriods minutes
+	rrule, err := ParseRRule("FREQ=MINUTELY;COUNT=500000")
+	if err != nil {
+		t.Fatalf("ParseRRule() error = %v", err)
+	}
+	dtstart := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	from := dtstart.AddDate(1, 0, 0)
+	err = rrule.Expand(dtstart, from, from.AddDate(0, 0, 1), func(time.Time) bool { return true })
+	if err == nil {
+		t.Error("Expand() should fail after maxPeriods periods")
+	}
+}
+
+func TestParseRRuleErrors(t *testing.T) {
+	for _, rule := range []string{"", "COUNT=3", "FREQ=DAILY;INTERVAL=0", "FREQ=WEEKLY;BYDAY=XX", "FREQ=DAILY;COUNT"} {
+		if _, err := ParseRRule(rule); err == nil {
+			t.Errorf("ParseRRule(%q) should fail", rule)
+		}
+	}
+}
+
+const recurringCalendar = `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:standup
+SUMMARY:Standup
+DTSTART;TZID=Europe/Berlin:20250303T090000
+DTEND;TZID=Europe/Berlin:20250303T091500
+RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR
+EXDATE;TZID=Europe/Berlin:20250311T090000
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID;TZID=Europe/Berlin:20250312T090000
+SUMMARY:Standup (moved)
+DTSTART;TZID=Europe/Berlin:20250312T100000
+DTEND;TZID=Europe/Berlin:20250312T101500
+END:VEVENT
+BEGIN:VEVENT
+UID:offsite
+SUMMARY:Offsite
+DTSTART;VALUE=DATE:20250309
+DTEND;VALUE=DATE:20250311
+END:VEVENT
+BEGIN:VEVENT
+UID:vacation
+SUMMARY:Vacation
+DTSTART;VALUE=DATE:20250101
+DTEND;VALUE=DATE:20250102
+RRULE:FREQ=YEARLY
+RDATE;VALUE=DATE:20250313
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestOccurrences(t *testing.T) {
+	berlin := mustLocation(t, "Europe/Berlin")
+	calendar, err := Parse(strings.NewReader(recurringCalendar), berlin)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, berlin)
+	occurrences, err := calendar.Occurrences(start, start.AddDate(0, 0, 4))
+	if err != nil {
+		t.Fatalf("Occurrences() error = %v", err)
+	}
+
+	var got []string
+	for _, occurrence := range occurrences {
+		got = append(got, occurrence.Start.Format("Mon 15:04 ")+occurrence.Summary)
+		if occurrence.RRule != nil {
+			t.Errorf("occurrence %s still has a rule", occurrence.Summary)
+		}
+	}
+	want := []string{
+		// The offsite started on Sunday and is still running on Monday
+		"Sun 00:00 Offsite",
+		"Mon 09:00 Standup",
+		"Wed 10:00 Standup (moved)",
+		"Thu 00:00 Vacation",
+		"Thu 09:00 Standup",
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	vacation := occurrences[3]
+	if !vacation.AllDay || !vacation.End.Equal(vacation.Start.AddDate(0, 0, 1)) {
+		t.Errorf("vacation = %s-%s, want a single all-day occurrence", vacation.Start, vacation.End)
+	}
+	standup := occurrences[4]
+	if standup.End.Sub(standup.Start) != 15*time.Minute {
+		t.Errorf("standup lasts %s, want 15m", standup.End.Sub(standup.Start))
+	}
+}
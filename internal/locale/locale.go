@@ -0,0 +1,165 @@
+// Package locale formats dates and times with localized day and month names.
+package locale
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is the locale used if none is configured.
+const DefaultLocale = "en"
+
+// timePresets are the named time formats accepted in place of a Go layout.
+var timePresets = map[string]string{
+	"24h": "15:04",
+	"12h": "3:04 PM",
+	"iso": "15:04:05",
+}
+
+// Locale holds the names and date layouts of a language.
+type Locale struct {
+	Name        string
+	days        [7]string
+	shortDays   [7]string
+	months      [12]string
+	shortMonths [12]string
+	// dateLayouts are the named date formats of the locale.
+	dateLayouts map[string]string
+}
+
+// locales are the supported locales by name.
+var locales = map[string]*Locale{
+	"en": {
+		Name:        "en",
+		days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		dateLayouts: map[string]string{
+			"short": "Mon 2006-01-02",
+			"long":  "Monday, January 2, 2006",
+		},
+	},
+	"de": {
+		Name:        "de",
+		days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		dateLayouts: map[string]string{
+			"short": "Mon, 02.01.2006",
+			"long":  "Monday, 2. January 2006",
+		},
+	},
+	"fr": {
+		Name:        "fr",
+		days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		dateLayouts: map[string]string{
+			"short": "Mon 02/01/2006",
+			"long":  "Monday 2 January 2006",
+		},
+	},
+	"es": {
+		Name:        "es",
+		days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortDays:   [7]string{"dom.", "lun.", "mar.", "mié.", "jue.", "vie.", "sáb."},
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene.", "feb.", "mar.", "abr.", "may.", "jun.", "jul.", "ago.", "sept.", "oct.", "nov.", "dic."},
+		dateLayouts: map[string]string{
+			"short": "Mon 02/01/2006",
+			"long":  "Monday, 2 de January de 2006",
+		},
+	},
+}
+
+// nameTokens are the layout elements replaced by localized names, longest first
+// so that "Monday" is not mistaken for "Mon".
+var nameTokens = []string{"Monday", "January", "Mon", "Jan"}
+
+// Get returns the locale with the given name, e.g. "de". Region suffixes like "de_AT" or "de-AT" are ignored.
+// An empty name returns the default locale.
+func Get(name string) (*Locale, error) {
+	if name == "" {
+		name = DefaultLocale
+	}
+	language, _, _ := strings.Cut(strings.ReplaceAll(strings.ToLower(name), "-", "_"), "_")
+	if locale, exists := locales[language]; exists {
+		return locale, nil
+	}
+
+	supported := make([]string, 0, len(locales))
+	for key := range locales {
+		supported = append(supported, key)
+	}
+	sort.Strings(supported)
+	return nil, fmt.Errorf("unsupported locale %q, use one of: %s", name, strings.Join(supported, ", "))
+}
+
+// TimeLayout returns the Go layout for a named time format: 24h, 12h or iso.
+// Any other value is assumed to be a Go layout and returned as is.
+func TimeLayout(format string) string {
+	if layout, exists := timePresets[strings.ToLower(format)]; exists {
+		return layout
+	}
+	return format
+}
+
+// DateLayout returns the Go layout for a named date format of the locale: short, long or iso.
+// Any other value is assumed to be a Go layout and returned as is. An empty format is the short format.
+func (l *Locale) DateLayout(format string) string {
+	switch name := strings.ToLower(format); name {
+	case "":
+		return l.dateLayouts["short"]
+	case "iso":
+		return "2006-01-02"
+	default:
+		if layout, exists := l.dateLayouts[name]; exists {
+			return layout
+		}
+	}
+	return format
+}
+
+// Format formats the time like time.Format, but with the day and month names of the locale.
+func (l *Locale) Format(t time.Time, layout string) string {
+	var result strings.Builder
+	for layout != "" {
+		index, token := nextNameToken(layout)
+		if index < 0 {
+			result.WriteString(t.Format(layout))
+			break
+		}
+
+		result.WriteString(t.Format(layout[:index]))
+		switch token {
+		case "Monday":
+			result.WriteString(l.days[t.Weekday()])
+		case "Mon":
+			result.WriteString(l.shortDays[t.Weekday()])
+		case "January":
+			result.WriteString(l.months[t.Month()-1])
+		case "Jan":
+			result.WriteString(l.shortMonths[t.Month()-1])
+		}
+		layout = layout[index+len(token):]
+	}
+	return result.String()
+}
+
+// nextNameToken finds the first day or month name element in the layout.
+// Returns -1 if the layout contains none.
+func nextNameToken(layout string) (int, string) {
+	firstIndex, firstToken := -1, ""
+	for _, token := range nameTokens {
+		index := strings.Index(layout, token)
+		if index >= 0 && (firstIndex < 0 || index < firstIndex) {
+			firstIndex, firstToken = index, token
+		}
+	}
+	return firstIndex, firstToken
+}
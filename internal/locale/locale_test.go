@@ -0,0 +1,64 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2025, time.March, 10, 14, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale string
+		layout string
+		want   string
+	}{
+		{"en", "short", "Mon 2025-03-10"},
+		{"en", "long", "Monday, March 10, 2025"},
+		{"de", "short", "Mo, 10.03.2025"},
+		{"de", "long", "Montag, 10. März 2025"},
+		{"fr", "long", "lundi 10 mars 2025"},
+		{"es", "long", "lunes, 10 de marzo de 2025"},
+		{"de-AT", "Mon 2 Jan", "Mo 10 Mär"},
+		{"fr", "iso", "2025-03-10"},
+		{"es", "02/01/2006 15:04", "10/03/2025 14:05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+" "+tt.layout, func(t *testing.T) {
+			locale, err := Get(tt.locale)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := locale.Format(date, locale.DateLayout(tt.layout)); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeLayout(t *testing.T) {
+	date := time.Date(2025, time.March, 10, 14, 5, 0, 0, time.UTC)
+
+	tests := map[string]string{
+		"24h":     "14:05",
+		"12h":     "2:05 PM",
+		"ISO":     "14:05:00",
+		"15h04":   "14h05",
+		"3:04 pm": "2:05 pm",
+	}
+	for format, want := range tests {
+		if got := date.Format(TimeLayout(format)); got != want {
+			t.Errorf("TimeLayout(%q) formats as %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestGetUnsupported(t *testing.T) {
+	if _, err := Get("xx"); err == nil {
+		t.Error("expected an error for an unsupported locale")
+	}
+	if locale, err := Get(""); err != nil || locale.Name != DefaultLocale {
+		t.Errorf("expected the default locale, got %v, %v", locale, err)
+	}
+}
@@ -0,0 +1,264 @@
+// Package manual reads events kept by hand in a YAML or markdown file, for things that are
+// not in any calendar such as personal routines or one-off reminders.
+//
+// A YAML file lists the events under events:
+//
+//	calendar: Personal
+//	events:
+//	  - title: Gym
+//	    time: 18:00-19:00
+//	    repeat: weekdays
+//	  - title: Dentist
+//	    date: 2025-03-14
+//	    time: "09:30-10:15"
+//	    location: Main Street 1
+//
+// A markdown file has one event per list item, made of the title, an optional date, an
+// optional time range and an optional repetition at the end. Headings name the calendar
+// of the events below them:
+//
+//	## Personal
+//	- Gym 18:00-19:00 weekdays
+//	- Dentist 2025-03-14 09:30-10:15
+//	- Pay rent 2025-01-01 monthly
+//
+// Events without a time are all-day events. A repetition is one of daily, weekdays,
+// weekends, weekly, monthly, yearly or a list of weekdays such as mon,wed,fri. Weekly,
+// monthly and yearly events repeat on the weekday or day of their date, the other
+// repetitions start at their date if they have one. Repeating events may end with
+// "until 2025-06-30".
+package manual
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dateLayout is the layout of dates in event files.
+const dateLayout = "2006-01-02"
+
+// Event is an event of a manual events file.
+type Event struct {
+	Title       string
+	Calendar    string
+	Location    string
+	Description string
+	// Date is the date of a single event or the first date of a repeating event, zero if a
+	// repeating event has no start.
+	Date time.Time
+	// Until is the last date a repeating event occurs on, zero if it repeats forever.
+	Until time.Time
+	// Start and End are the time of day of the event, both are nil for all-day events.
+	Start, End *Clock
+	Repeat     *Repeat
+}
+
+// Clock is a time of day.
+type Clock struct {
+	Hour, Minute int
+}
+
+// yamlFile is the format of YAML event files.
+type yamlFile struct {
+	Calendar string      `yaml:"calendar"`
+	Events   []yamlEvent `yaml:"events"`
+}
+
+// yamlEvent is a single event of a YAML event file.
+type yamlEvent struct {
+	Title       string `yaml:"title"`
+	Date        string `yaml:"date"`
+	Until       string `yaml:"until"`
+	Time        string `yaml:"time"`
+	Repeat      string `yaml:"repeat"`
+	Calendar    string `yaml:"calendar"`
+	Location    string `yaml:"location"`
+	Description string `yaml:"description"`
+}
+
+// ReadFile reads the events of a YAML (.yaml, .yml) or markdown file. Events without a
+// calendar are assigned to one named after the file.
+func ReadFile(path string) ([]Event, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseYAML(data, calendar)
+	default:
+		return ParseMarkdown(string(data), calendar), nil
+	}
+}
+
+// ParseYAML parses the events of a YAML file, using calendar for events that name none.
+func ParseYAML(data []byte, calendar string) ([]Event, error) {
+	var file yamlFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Calendar != "" {
+		calendar = file.Calendar
+	}
+
+	events := make([]Event, 0, len(file.Events))
+	for i, item := range file.Events {
+		if item.Title == "" {
+			return nil, fmt.Errorf("event %d has no title", i+1)
+		}
+		event := Event{
+			Title:       item.Title,
+			Calendar:    item.Calendar,
+			Location:    item.Location,
+			Description: item.Description,
+		}
+		if event.Calendar == "" {
+			event.Calendar = calendar
+		}
+
+		var err error
+		if item.Date != "" {
+			if event.Date, err = time.Parse(dateLayout, item.Date); err != nil {
+				return nil, fmt.Errorf("event %s: invalid date %q", item.Title, item.Date)
+			}
+		}
+		if item.Until != "" {
+			if event.Until, err = time.Parse(dateLayout, item.Until); err != nil {
+				return nil, fmt.Errorf("event %s: invalid until date %q", item.Title, item.Until)
+			}
+		}
+		if item.Time != "" {
+			if event.Start, event.End, err = parseTimeRange(item.Time); err != nil {
+				return nil, fmt.Errorf("event %s: %w", item.Title, err)
+			}
+		}
+		if item.Repeat != "" {
+			if event.Repeat, err = ParseRepeat(item.Repeat); err != nil {
+				return nil, fmt.Errorf("event %s: %w", item.Title, err)
+			}
+		}
+		if err := event.validate(); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// ParseMarkdown parses the list items of a markdown file as events, using calendar for
+// events before the first heading. Items that are not events, e.g. other bullets of a notes
+// file, are skipped with a warning.
+func ParseMarkdown(data string, calendar string) []Event {
+	var events []Event
+	for number, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if heading, found := strings.CutPrefix(line, "#"); found {
+			calendar = strings.TrimSpace(strings.TrimLeft(heading, "#"))
+			continue
+		}
+
+		item, found := strings.CutPrefix(line, "- ")
+		if !found {
+			item, found = strings.CutPrefix(line, "* ")
+		}
+		if !found {
+			continue
+		}
+
+		event, err := parseItem(strings.TrimSpace(item))
+		if err != nil {
+			log.Printf("Warning: skipping line %d, it is not an event: %v", number+1, err)
+			continue
+		}
+		event.Calendar = calendar
+		events = append(events, event)
+	}
+	return events
+}
+
+// parseItem parses a markdown list item. The date and time range are recognized anywhere
+// in the item, the repetition and its end at the end of it. The remaining words form the title.
+func parseItem(item string) (Event, error) {
+	var event Event
+	var title []string
+	words := strings.Fields(item)
+	if n := len(words); n > 2 && strings.EqualFold(words[n-2], "until") {
+		if until, err := time.Parse(dateLayout, words[n-1]); err == nil {
+			event.Until = until
+			words = words[:n-2]
+		}
+	}
+	for i, word := range words {
+		if date, err := time.Parse(dateLayout, word); err == nil && event.Date.IsZero() {
+			event.Date = date
+			continue
+		}
+		if start, end, err := parseTimeRange(word); err == nil && event.Start == nil {
+			event.Start, event.End = start, end
+			continue
+		}
+		// Only the last word is a repetition, so titles may contain words like "daily"
+		if i == len(words)-1 && len(title) > 0 {
+			if repeat, err := ParseRepeat(word); err == nil {
+				event.Repeat = repeat
+				continue
+			}
+		}
+		title = append(title, word)
+	}
+
+	event.Title = strings.Join(title, " ")
+	if event.Title == "" {
+		return Event{}, fmt.Errorf("event %q has no title", item)
+	}
+	return event, event.validate()
+}
+
+// validate reports events that cannot be placed in the calendar.
+func (e Event) validate() error {
+	if e.Repeat == nil {
+		if e.Date.IsZero() {
+			return fmt.Errorf("event %s needs a date or a repetition", e.Title)
+		}
+		return nil
+	}
+	if e.Repeat.needsDate() && e.Date.IsZero() {
+		return fmt.Errorf("event %s repeats %s and needs a date", e.Title, e.Repeat.Freq)
+	}
+	return nil
+}
+
+// parseTimeRange parses a time of day or a range of them, e.g. 18:00-19:00. A single time
+// is an event of an hour.
+func parseTimeRange(value string) (*Clock, *Clock, error) {
+	startValue, endValue, isRange := strings.Cut(value, "-")
+	start, err := parseClock(startValue)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isRange {
+		return start, &Clock{Hour: start.Hour + 1, Minute: start.Minute}, nil
+	}
+	end, err := parseClock(endValue)
+	if err != nil {
+		return nil, nil, err
+	}
+	return start, end, nil
+}
+
+// parseClock parses a time of day in the form 15:04.
+func parseClock(value string) (*Clock, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return &Clock{Hour: parsed.Hour(), Minute: parsed.Minute()}, nil
+}
@@ -0,0 +1,134 @@
+package manual
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// formatOccurrences formats the occurrences for comparison.
+func formatOccurrences(occurrences []Occurrence) string {
+	var formatted []string
+	for _, occurrence := range occurrences {
+		if occurrence.AllDay {
+			formatted = append(formatted, occurrence.Start.Format("Mon")+" "+occurrence.Title)
+		} else {
+			formatted = append(formatted, occurrence.Start.Format("Mon 15:04-")+occurrence.End.Format("15:04 ")+occurrence.Title)
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func TestParseMarkdown(t *testing.T) {
+	events := ParseMarkdown(`# Notes
+Free text is ignored.
+- Buy milk
+
+- Gym 18:00-19:00 weekdays
+* Dentist 2025-03-14 09:30-10:15
+
+## Team
+- Retro 2025-03-07 15:00 weekly until 2025-03-31
+- Daily planning 08:45-09:00 daily
+- Pay rent 2025-01-12 monthly
+`, "events")
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(events))
+	}
+
+	gym := events[0]
+	if gym.Title != "Gym" || gym.Calendar != "Notes" || gym.Repeat == nil || len(gym.Repeat.Weekdays) != 5 || *gym.End != (Clock{19, 0}) {
+		t.Errorf("gym = %+v", gym)
+	}
+	retro := events[2]
+	if retro.Calendar != "Team" || retro.Until.Format(dateLayout) != "2025-03-31" || *retro.End != (Clock{16, 0}) {
+		t.Errorf("retro = %+v", retro)
+	}
+	// The repetition is only recognized as the last word
+	if events[3].Title != "Daily planning" || events[3].Repeat.Freq != Daily {
+		t.Errorf("planning = %+v", events[3])
+	}
+	if events[4].Start != nil || events[4].Repeat.Freq != Monthly {
+		t.Errorf("rent = %+v", events[4])
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	events, err := ParseYAML([]byte(`calendar: Personal
+events:
+  - title: Gym
+    time: 18:00-19:00
+    repeat: mon,wed,fri
+  - title: Dentist
+    date: 2025-03-14
+    time: "09:30-10:15"
+    location: Main Street 1
+    calendar: Health
+`), "events")
+	if err != nil {
+		t.Fatalf("ParseYAML() error = %v", err)
+	}
+	if len(events) != 2 || events[0].Calendar != "Personal" || len(events[0].Repeat.Weekdays) != 3 {
+		t.Fatalf("events = %+v", events)
+	}
+	if events[1].Calendar != "Health" || events[1].Location != "Main Street 1" || *events[1].Start != (Clock{9, 30}) {
+		t.Errorf("dentist = %+v", events[1])
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	// Markdown items that are not events are skipped instead
+	for _, item := range []string{
+		"- Gym 18:00-19:00",
+		"- 2025-03-10 10:00",
+		"- Retro 10:00 weekly",
+	} {
+		if events := ParseMarkdown(item, "events"); len(events) != 0 {
+			t.Errorf("ParseMarkdown(%q) = %+v, want no events", item, events)
+		}
+	}
+	for _, data := range []string{
+		"events:\n  - date: 2025-03-10\n",
+		"events:\n  - title: x\n    date: 10.03.2025\n",
+		"events:\n  - title: x\n    time: 25:00\n    repeat: daily\n",
+		"events:\n  - title: x\n    repeat: sometimes\n",
+	} {
+		if _, err := ParseYAML([]byte(data), "events"); err == nil {
+			t.Errorf("ParseYAML(%q) should fail", data)
+		}
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	events := ParseMarkdown(`
+- Gym 18:00-19:00 weekdays
+- Release 2025-03-12
+- Retro 2025-03-07 15:00 weekly
+- Night shift 2025-03-09 22:00-06:00
+- Sprint 2025-03-11 09:00 mon,tue until 2025-03-11
+- Birthday 2020-03-13 yearly
+`, "events")
+
+	// Monday to Friday
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	got := formatOccurrences(Occurrences(events, start, start.AddDate(0, 0, 5)))
+	want := "Sun 22:00-06:00 Night shift, Mon 18:00-19:00 Gym, Tue 09:00-10:00 Sprint, Tue 18:00-19:00 Gym, " +
+		"Wed Release, Wed 18:00-19:00 Gym, Thu Birthday, Thu 18:00-19:00 Gym, Fri 15:00-16:00 Retro, Fri 18:00-19:00 Gym"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "routines.md")
+	if err := os.WriteFile(path, []byte("- Gym 18:00 daily\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	events, err := ReadFile(path)
+	if err != nil || len(events) != 1 || events[0].Calendar != "routines" {
+		t.Errorf("ReadFile() = %+v, %v", events, err)
+	}
+}
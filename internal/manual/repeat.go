@@ -0,0 +1,145 @@
+package manual
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Repetition frequencies.
+const (
+	Daily   = "daily"
+	Weekly  = "weekly"
+	Monthly = "monthly"
+	Yearly  = "yearly"
+)
+
+// weekdayNames maps the abbreviations of the days of the week.
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Repeat is the repetition of an event.
+type Repeat struct {
+	Freq string
+	// Weekdays are the days a weekly event occurs on, the weekday of its date if empty.
+	Weekdays []time.Weekday
+}
+
+// ParseRepeat parses a repetition: daily, weekdays, weekends, weekly, monthly, yearly or
+// a comma separated list of weekdays such as mon,wed,fri.
+func ParseRepeat(value string) (*Repeat, error) {
+	switch value = strings.ToLower(strings.TrimSpace(value)); value {
+	case Daily, Weekly, Monthly, Yearly:
+		return &Repeat{Freq: value}, nil
+	case "weekdays":
+		return &Repeat{Freq: Weekly, Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}, nil
+	case "weekends":
+		return &Repeat{Freq: Weekly, Weekdays: []time.Weekday{time.Saturday, time.Sunday}}, nil
+	}
+
+	repeat := &Repeat{Freq: Weekly}
+	for _, name := range strings.Split(value, ",") {
+		weekday, ok := weekdayNames[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("invalid repetition %q, expected daily, weekdays, weekends, weekly, monthly, yearly or days like mon,wed,fri", value)
+		}
+		repeat.Weekdays = append(repeat.Weekdays, weekday)
+	}
+	return repeat, nil
+}
+
+// needsDate reports whether the repetition depends on the date of the event.
+func (r *Repeat) needsDate() bool {
+	return r.Freq == Monthly || r.Freq == Yearly || (r.Freq == Weekly && len(r.Weekdays) == 0)
+}
+
+// occursOn reports whether the event takes place on the given date.
+func (e Event) occursOn(date time.Time) bool {
+	if e.Repeat == nil {
+		return sameDate(e.Date, date)
+	}
+	if !e.Date.IsZero() && dateBefore(date, e.Date) {
+		return false
+	}
+	if !e.Until.IsZero() && dateBefore(e.Until, date) {
+		return false
+	}
+
+	switch e.Repeat.Freq {
+	case Weekly:
+		if len(e.Repeat.Weekdays) == 0 {
+			return date.Weekday() == e.Date.Weekday()
+		}
+		for _, weekday := range e.Repeat.Weekdays {
+			if date.Weekday() == weekday {
+				return true
+			}
+		}
+		return false
+	case Monthly:
+		return date.Day() == e.Date.Day()
+	case Yearly:
+		return date.Month() == e.Date.Month() && date.Day() == e.Date.Day()
+	default:
+		return true
+	}
+}
+
+// Occurrence is a single occurrence of an event.
+type Occurrence struct {
+	Event
+	Start, End time.Time
+	AllDay     bool
+}
+
+// Occurrences returns the occurrences of the events overlapping [start, end), sorted by
+// start. Times of day are in the location of start.
+func Occurrences(events []Event, start, end time.Time) []Occurrence {
+	loc := start.Location()
+	var occurrences []Occurrence
+	// Start a day early for events running past midnight
+	first := time.Date(start.Year(), start.Month(), start.Day()-1, 0, 0, 0, 0, loc)
+	for day := first; day.Before(end); day = day.AddDate(0, 0, 1) {
+		for _, event := range events {
+			if !event.occursOn(day) {
+				continue
+			}
+
+			occurrence := Occurrence{Event: event, Start: day, End: day.AddDate(0, 0, 1), AllDay: event.Start == nil}
+			if event.Start != nil {
+				occurrence.Start = event.Start.on(day)
+				occurrence.End = event.End.on(day)
+				if !occurrence.End.After(occurrence.Start) {
+					occurrence.End = event.End.on(day.AddDate(0, 0, 1))
+				}
+			}
+			if occurrence.Start.Before(end) && occurrence.End.After(start) {
+				occurrences = append(occurrences, occurrence)
+			}
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Start.Before(occurrences[j].Start)
+	})
+	return occurrences
+}
+
+// on returns the time of day on the given date.
+func (c *Clock) on(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), c.Hour, c.Minute, 0, 0, date.Location())
+}
+
+// sameDate reports whether both times fall on the same calendar date, ignoring their locations.
+func sameDate(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
+}
+
+// dateBefore reports whether the calendar date of a is before the one of b.
+func dateBefore(a, b time.Time) bool {
+	return time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC).
+		Before(time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,51 @@
+package meetings
+
+import (
+	"regexp"
+	"strings"
+)
+
+// meetingURLPatterns match the join links of common video conferencing services.
+var meetingURLPatterns = []*regexp.Regexp{
+	// Zoom: https://us02web.zoom.us/j/123456789?pwd=..., https://company.zoom.us/my/name
+	regexp.MustCompile(`https://(?:[\w-]+\.)?zoom\.(?:us|com)/(?:j|my|w|s)/[^\s"'<>]+`),
+	// Google Meet: https://meet.google.com/abc-defg-hij
+	regexp.MustCompile(`https://meet\.google\.com/[a-z]{3}-[a-z]{4}-[a-z]{3}(?:\?[^\s"'<>]*)?`),
+	// Microsoft Teams: https://teams.microsoft.com/l/meetup-join/..., https://teams.live.com/meet/...
+	regexp.MustCompile(`https://teams\.(?:microsoft|live)\.com/(?:l/meetup-join|meet)/[^\s"'<>]+`),
+	// Webex: https://company.webex.com/meet/name, https://company.webex.com/company/j.php?MTID=...
+	regexp.MustCompile(`https://[\w-]+\.webex\.com/[^\s"'<>]+`),
+	// GoTo Meeting: https://meet.goto.com/123456789, https://global.gotomeeting.com/join/123456789
+	regexp.MustCompile(`https://(?:meet\.goto\.com|(?:[\w-]+\.)?gotomeeting\.com/join)/[^\s"'<>]+`),
+	// Whereby and Jitsi
+	regexp.MustCompile(`https://(?:whereby\.com|meet\.jit\.si)/[^\s"'<>]+`),
+}
+
+// ExtractURL returns the first video conferencing link found in the given texts, which are searched in order.
+// Returns an empty string if none of the texts contains a known meeting link.
+func ExtractURL(texts ...string) string {
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+
+		// Use the link that appears first in the text, invites often mention the main link first
+		found, foundAt := "", -1
+		for _, pattern := range meetingURLPatterns {
+			location := pattern.FindStringIndex(text)
+			if location != nil && (foundAt == -1 || location[0] < foundAt) {
+				found, foundAt = text[location[0]:location[1]], location[0]
+			}
+		}
+		if found != "" {
+			return cleanURL(found)
+		}
+	}
+	return ""
+}
+
+// cleanURL removes trailing punctuation and HTML entities that are picked up from the surrounding text.
+func cleanURL(url string) string {
+	url = strings.ReplaceAll(url, "&amp;", "&")
+	return strings.TrimRight(url, ".,;:!?)]}>")
+}
@@ -0,0 +1,50 @@
+package meetings
+
+import "testing"
+
+func TestExtractURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+		want  string
+	}{
+		{
+			name:  "zoom in description",
+			texts: []string{"Room 4", "Join Zoom Meeting\nhttps://us02web.zoom.us/j/123456789?pwd=abc123.\nMeeting ID: 123"},
+			want:  "https://us02web.zoom.us/j/123456789?pwd=abc123",
+		},
+		{
+			name:  "google meet in location",
+			texts: []string{"https://meet.google.com/abc-defg-hij", "https://us02web.zoom.us/j/1"},
+			want:  "https://meet.google.com/abc-defg-hij",
+		},
+		{
+			name:  "teams link in html",
+			texts: []string{`<a href="https://teams.microsoft.com/l/meetup-join/19%3ameeting_abc%40thread.v2/0?context=%7b%7d">Join</a>`},
+			want:  "https://teams.microsoft.com/l/meetup-join/19%3ameeting_abc%40thread.v2/0?context=%7b%7d",
+		},
+		{
+			name:  "first link in text wins",
+			texts: []string{"Primary: https://meet.google.com/abc-defg-hij backup https://company.zoom.us/my/room"},
+			want:  "https://meet.google.com/abc-defg-hij",
+		},
+		{
+			name:  "html entities are decoded",
+			texts: []string{"https://company.webex.com/company/j.php?MTID=m123&amp;ref=x"},
+			want:  "https://company.webex.com/company/j.php?MTID=m123&ref=x",
+		},
+		{
+			name:  "no meeting link",
+			texts: []string{"Office", "Agenda: https://docs.example.com/notes"},
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractURL(tt.texts...); got != tt.want {
+				t.Errorf("ExtractURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
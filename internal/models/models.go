@@ -11,4 +11,60 @@ type CalendarEvent struct {
 	Description string    `json:"description,omitempty"`
 	Location    string    `json:"location,omitempty"`
 	Attendees   []string  `json:"attendees,omitempty"`
+	// CalendarName is the name of the calendar the event belongs to.
+	CalendarName string `json:"calendar_name,omitempty"`
+	CalendarID   string `json:"calendar_id,omitempty"`
+	AccountID    string `json:"account_id,omitempty"`
+	// AccountName is the human readable name of the account, e.g. its email address, if the provider knows it.
+	AccountName string `json:"account_name,omitempty"`
+	// Color is the color of the calendar as reported by the provider, usually a hex string like "#3b82f6".
+	Color string `json:"color,omitempty"`
+	// TimeZone is the IANA name of the timezone the event was created in, if known.
+	TimeZone string `json:"time_zone,omitempty"`
+	// MeetingURL is the video conferencing link of the event, if any.
+	MeetingURL string `json:"meeting_url,omitempty"`
+	// Provider is the name of the provider the event was retrieved from.
+	Provider string `json:"provider,omitempty"`
+	// AllDay is set for events without a time of day, they span whole days from midnight to midnight.
+	AllDay bool `json:"all_day,omitempty"`
+	// Status is the status of the event itself: confirmed, tentative or cancelled.
+	Status string `json:"status,omitempty"`
+	// ResponseStatus is the response of the user to the invitation: accepted, declined, tentative or needsAction.
+	// It is empty for events the user organizes or was not invited to.
+	ResponseStatus string `json:"response_status,omitempty"`
+	// TaskID is the task the event is a time block of, if the provider manages tasks.
+	TaskID string `json:"task_id,omitempty"`
+}
+
+// Event statuses shared by all providers.
+const (
+	StatusConfirmed = "confirmed"
+	StatusTentative = "tentative"
+	StatusCancelled = "cancelled"
+)
+
+// Invitation responses shared by all providers.
+const (
+	ResponseAccepted    = "accepted"
+	ResponseDeclined    = "declined"
+	ResponseTentative   = "tentative"
+	ResponseNeedsAction = "needsAction"
+)
+
+// Task represents a task of a task list, e.g. a to-do of the Morgen task manager.
+type Task struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Due is when the task is due, zero if it has no due date.
+	Due time.Time `json:"due,omitempty"`
+	// Scheduled is the start of the calendar block the task is scheduled in, zero if it is not scheduled.
+	Scheduled time.Time `json:"scheduled,omitempty"`
+	Completed bool      `json:"completed,omitempty"`
+	// Overdue is set for open tasks that were due before the range of the agenda.
+	Overdue bool `json:"overdue,omitempty"`
+	// Priority ranges from 1 (highest) to 9 (lowest), 0 means no priority.
+	Priority int `json:"priority,omitempty"`
+	// Provider is the name of the provider the task was retrieved from.
+	Provider string `json:"provider,omitempty"`
 }
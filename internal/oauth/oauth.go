@@ -0,0 +1,257 @@
+// Package oauth implements the OAuth2 authorization-code flow with PKCE and a loopback
+// redirect, as used by desktop applications, and keeps the resulting tokens fresh.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+)
+
+// callbackPath is the path of the loopback redirect URI.
+const callbackPath = "/callback"
+
+// Client performs the OAuth2 flows of one provider.
+type Client struct {
+	config configs.OAuthConfig
+	http   *http.Client
+	now    func() time.Time
+}
+
+// NewClient creates a client for the given OAuth configuration.
+func NewClient(config configs.OAuthConfig) *Client {
+	return &Client{
+		config: config,
+		http:   &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}
+}
+
+// tokenResponse is the JSON response of the token endpoint.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// callbackResult is what the loopback redirect received.
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// Authorize runs the authorization-code flow. It listens on a loopback port, calls open with
+// the authorization URL, which should be shown to the user in a browser, and waits until the
+// browser is redirected back with a code or ctx is done. The code is then exchanged for a token.
+func (c *Client) Authorize(ctx context.Context, open func(authURL string) error) (*Token, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", c.config.RedirectPort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the redirect: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s%s", listener.Addr(), callbackPath)
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state && query.Get("code") == "" && query.Get("error") == "" {
+			// Not the redirect of this authorization, e.g. a reload of an old tab, keep waiting
+			http.NotFound(w, r)
+			return
+		}
+		result := parseCallback(query, state)
+		if result.err != nil {
+			http.Error(w, fmt.Sprintf("Authorization failed: %v", result.err), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Authorization complete, you can close this window.")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	defer server.Close()
+
+	if err := open(c.authURL(redirectURI, state, verifier)); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("authorization was not completed: %w", ctx.Err())
+	case result := <-results:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return c.exchange(ctx, url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {result.code},
+			"redirect_uri":  {redirectURI},
+			"code_verifier": {verifier},
+		})
+	}
+}
+
+// Refresh exchanges the refresh token of a token for a new access token. The refresh
+// token is kept if the server does not issue a new one.
+func (c *Client) Refresh(ctx context.Context, token *Token) (*Token, error) {
+	if token == nil || token.RefreshToken == "" {
+		return nil, errors.New("token cannot be refreshed, it has no refresh token")
+	}
+
+	refreshed, err := c.exchange(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {token.RefreshToken},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+	return refreshed, nil
+}
+
+// validate checks that the configuration has everything the flow needs.
+func (c *Client) validate() error {
+	var missing []string
+	if c.config.ClientID == "" {
+		missing = append(missing, "client_id")
+	}
+	if c.config.AuthURL == "" {
+		missing = append(missing, "auth_url")
+	}
+	if c.config.TokenURL == "" {
+		missing = append(missing, "token_url")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("oauth configuration is missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// authURL builds the URL the user authorizes the application at.
+func (c *Client) authURL(redirectURI, state, verifier string) string {
+	challenge := sha256.Sum256([]byte(verifier))
+
+	query := url.Values{}
+	for key, value := range c.config.AuthParams {
+		query.Set(key, value)
+	}
+	query.Set("response_type", "code")
+	query.Set("client_id", c.config.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("state", state)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	if len(c.config.Scopes) > 0 {
+		query.Set("scope", strings.Join(c.config.Scopes, " "))
+	}
+
+	separator := "?"
+	if strings.Contains(c.config.AuthURL, "?") {
+		separator = "&"
+	}
+	return c.config.AuthURL + separator + query.Encode()
+}
+
+// exchange posts a grant to the token endpoint and returns the issued token.
+func (c *Client) exchange(ctx context.Context, form url.Values) (*Token, error) {
+	form.Set("client_id", c.config.ClientID)
+	if c.config.ClientSecret != "" {
+		form.Set("client_secret", c.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	// The body is left out of errors, it may hold tokens
+	var response tokenResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("token request failed with status %d", resp.StatusCode)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("token request failed: %s %s", response.Error, response.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || response.AccessToken == "" {
+		return nil, fmt.Errorf("token request failed with status %d", resp.StatusCode)
+	}
+
+	token := &Token{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
+		TokenType:    response.TokenType,
+	}
+	if response.ExpiresIn > 0 {
+		token.Expiry = c.now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// parseCallback checks the state of the redirect and returns the authorization code or error.
+func parseCallback(query url.Values, state string) callbackResult {
+	if query.Get("state") != state {
+		return callbackResult{err: errors.New("redirect has an invalid state")}
+	}
+	if errorCode := query.Get("error"); errorCode != "" {
+		return callbackResult{err: fmt.Errorf("authorization denied: %s %s", errorCode, query.Get("error_description"))}
+	}
+	code := query.Get("code")
+	if code == "" {
+		return callbackResult{err: errors.New("redirect has no authorization code")}
+	}
+	return callbackResult{code: code}
+}
+
+// randomString returns a URL safe random string, used for the state and the PKCE verifier.
+func randomString() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
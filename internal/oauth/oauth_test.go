@@ -0,0 +1,291 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+)
+
+// fakeServer is a minimal OAuth2 authorization server. Its authorization endpoint
+// approves every request and redirects straight back with a code.
+type fakeServer struct {
+	*httptest.Server
+	mu         sync.Mutex
+	challenge  string
+	grants     []url.Values
+	refreshes  int
+	denyAccess bool
+	// tokenBody replaces the response of the token endpoint with a failure if it is set.
+	tokenBody string
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	f := &fakeServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("code_challenge_method") != "S256" || query.Get("client_id") != "client" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		f.challenge = query.Get("code_challenge")
+		f.mu.Unlock()
+
+		redirect, _ := url.Parse(query.Get("redirect_uri"))
+		values := url.Values{"state": {query.Get("state")}}
+		if f.denyAccess {
+			values.Set("error", "access_denied")
+		} else {
+			values.Set("code", "auth-code")
+		}
+		redirect.RawQuery = values.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.grants = append(f.grants, r.PostForm)
+
+		if f.tokenBody != "" {
+			http.Error(w, f.tokenBody, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+			if r.PostForm.Get("code") != "auth-code" || base64.RawURLEncoding.EncodeToString(verifier[:]) != f.challenge {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "access-1", "refresh_token": "refresh-1", "token_type": "Bearer", "expires_in": 3600,
+			})
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") != "refresh-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+			f.refreshes++
+			json.NewEncoder(w).Encode(map[string]any{"access_token": "access-2", "expires_in": 3600})
+		}
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeServer) config() configs.OAuthConfig {
+	return configs.OAuthConfig{
+		ClientID:   "client",
+		AuthURL:    f.URL + "/authorize",
+		TokenURL:   f.URL + "/token",
+		Scopes:     []string{"calendar.read", "offline"},
+		AuthParams: map[string]string{"access_type": "offline"},
+	}
+}
+
+// browser follows the authorization URL like a browser would, including the redirect to the loopback server.
+func browser(authURL string) error {
+	go func() {
+		resp, err := http.Get(authURL)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	return nil
+}
+
+func TestAuthorize(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.config())
+
+	var authURL string
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	token, err := client.Authorize(ctx, func(u string) error {
+		authURL = u
+		return browser(u)
+	})
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" {
+		t.Errorf("Authorize() = %+v, want the issued tokens", token)
+	}
+	if token.Expiry.IsZero() {
+		t.Error("Authorize() did not set the expiry")
+	}
+
+	parsed, _ := url.Parse(authURL)
+	if scope := parsed.Query().Get("scope"); scope != "calendar.read offline" {
+		t.Errorf("scope = %q, want %q", scope, "calendar.read offline")
+	}
+	if accessType := parsed.Query().Get("access_type"); accessType != "offline" {
+		t.Errorf("access_type = %q, want %q", accessType, "offline")
+	}
+	if redirect := parsed.Query().Get("redirect_uri"); !strings.HasPrefix(redirect, "http://127.0.0.1:") {
+		t.Errorf("redirect_uri = %q, want a loopback address", redirect)
+	}
+}
+
+func TestAuthorizeDenied(t *testing.T) {
+	server := newFakeServer(t)
+	server.denyAccess = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := NewClient(server.config()).Authorize(ctx, browser)
+	if err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("Authorize() error = %v, want access_denied", err)
+	}
+}
+
+func TestAuthorizeIgnoresStrayCallbacks(t *testing.T) {
+	server := newFakeServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	token, err := NewClient(server.config()).Authorize(ctx, func(authURL string) error {
+		// A request without a code, error or the state, like a reload of an old tab, comes first
+		parsed, _ := url.Parse(authURL)
+		resp, err := http.Get(parsed.Query().Get("redirect_uri") + "?state=old")
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("stray callback status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+		}
+		return browser(authURL)
+	})
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if token.AccessToken != "access-1" {
+		t.Errorf("Authorize() = %+v, want the issued tokens", token)
+	}
+}
+
+func TestRefreshErrorOmitsBody(t *testing.T) {
+	server := newFakeServer(t)
+	server.tokenBody = "upstream failure for refresh-1"
+
+	_, err := NewClient(server.config()).Refresh(context.Background(), &Token{RefreshToken: "refresh-1"})
+	if err == nil {
+		t.Fatal("Refresh() should fail")
+	}
+	if !strings.Contains(err.Error(), "status 500") || strings.Contains(err.Error(), "refresh-1") {
+		t.Errorf("Refresh() error = %v, want the status without the body", err)
+	}
+}
+
+func TestAuthorizeTimeout(t *testing.T) {
+	server := newFakeServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := NewClient(server.config()).Authorize(ctx, func(string) error { return nil })
+	if err == nil {
+		t.Error("Authorize() should fail when the browser never returns")
+	}
+}
+
+func TestAuthorizeMissingConfig(t *testing.T) {
+	_, err := NewClient(configs.OAuthConfig{ClientID: "client"}).Authorize(context.Background(), browser)
+	if err == nil || !strings.Contains(err.Error(), "auth_url, token_url") {
+		t.Errorf("Authorize() error = %v, want the missing fields", err)
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "tokens"))
+
+	if _, err := store.Load("google"); err != ErrNoToken {
+		t.Errorf("Load() error = %v, want ErrNoToken", err)
+	}
+
+	token := &Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)}
+	if err := store.Save("google", token); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := store.Load("google")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if *loaded != *token {
+		t.Errorf("Load() = %+v, want %+v", loaded, token)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(store.path("google"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("token file mode = %04o, want 0600", perm)
+		}
+	}
+}
+
+func TestTokenSourceRefreshes(t *testing.T) {
+	server := newFakeServer(t)
+	store := NewStore(t.TempDir())
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	client := NewClient(server.config())
+	client.now = func() time.Time { return now }
+
+	if err := store.Save("google", &Token{AccessToken: "access-1", RefreshToken: "refresh-1", Expiry: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	source := NewTokenSource(client, store, "google")
+
+	accessToken, err := source.AccessToken(context.Background())
+	if err != nil || accessToken != "access-1" {
+		t.Fatalf("AccessToken() = %q, %v, want the stored token", accessToken, err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	accessToken, err = source.AccessToken(context.Background())
+	if err != nil || accessToken != "access-2" {
+		t.Fatalf("AccessToken() = %q, %v, want the refreshed token", accessToken, err)
+	}
+	if server.refreshes != 1 {
+		t.Errorf("refreshes = %d, want 1", server.refreshes)
+	}
+
+	stored, err := store.Load("google")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.AccessToken != "access-2" || stored.RefreshToken != "refresh-1" {
+		t.Errorf("stored token = %+v, want the refreshed token with the old refresh token", stored)
+	}
+}
+
+func TestTokenSourceNotAuthorized(t *testing.T) {
+	source := NewTokenSource(NewClient(configs.OAuthConfig{}), NewStore(t.TempDir()), "google")
+	_, err := source.AccessToken(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "agenda auth google") {
+		t.Errorf("AccessToken() error = %v, want a hint to run the auth command", err)
+	}
+}
@@ -0,0 +1,143 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// expiryMargin refreshes tokens a little before they expire, so they do not expire during a request.
+const expiryMargin = time.Minute
+
+// ErrNoToken is returned when no token has been stored for a provider yet.
+var ErrNoToken = errors.New("no token stored")
+
+// Token is an OAuth2 token as stored on disk.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// Valid reports whether the access token can still be used at the given time.
+// Tokens without an expiry are assumed to be valid.
+func (t *Token) Valid(now time.Time) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || now.Add(expiryMargin).Before(t.Expiry)
+}
+
+// Store keeps the tokens of providers as JSON files only readable by the user.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a token store in the given directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// path returns the file the token of the named provider is stored in.
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// Load reads the token of the named provider. It returns ErrNoToken if none is stored.
+func (s *Store) Load(name string) (*Token, error) {
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse token %s: %w", s.path(name), err)
+	}
+	return &token, nil
+}
+
+// Save writes the token of the named provider with 0600 permissions.
+func (s *Store) Save(name string, token *Token) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	// Write to a temporary file first so a failed write never leaves a truncated token behind.
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(name)); err != nil {
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+	return nil
+}
+
+// TokenSource returns valid access tokens of a provider. Expired tokens are refreshed
+// and the new token is stored, so the user only has to authorize once.
+type TokenSource struct {
+	client *Client
+	store  *Store
+	name   string
+	token  *Token
+}
+
+// NewTokenSource creates a token source for the named provider.
+func NewTokenSource(client *Client, store *Store, name string) *TokenSource {
+	return &TokenSource{client: client, store: store, name: name}
+}
+
+// AccessToken returns a valid access token, refreshing it if needed.
+func (s *TokenSource) AccessToken(ctx context.Context) (string, error) {
+	if s.token == nil {
+		token, err := s.store.Load(s.name)
+		if errors.Is(err, ErrNoToken) {
+			return "", fmt.Errorf("%s is not authorized yet, run: agenda auth %s", s.name, s.name)
+		}
+		if err != nil {
+			return "", err
+		}
+		s.token = token
+	}
+
+	if s.token.Valid(s.client.now()) {
+		return s.token.AccessToken, nil
+	}
+
+	token, err := s.client.Refresh(ctx, s.token)
+	if err != nil {
+		return "", fmt.Errorf("%w, run: agenda auth %s", err, s.name)
+	}
+	if err := s.store.Save(s.name, token); err != nil {
+		return "", err
+	}
+	s.token = token
+	return s.token.AccessToken, nil
+}
@@ -0,0 +1,28 @@
+package paths
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := map[string]string{
+		"~":                home,
+		"~/calendars/work": filepath.Join(home, "calendars", "work"),
+		"/etc/agenda":      "/etc/agenda",
+		"relative/~/path":  "relative/~/path",
+		"~other/calendars": "~other/calendars",
+	}
+	for path, want := range tests {
+		got, err := ExpandHome(path)
+		if err != nil {
+			t.Fatalf("ExpandHome(%q) error = %v", path, err)
+		}
+		if got != want {
+			t.Errorf("ExpandHome(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
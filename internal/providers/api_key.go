@@ -0,0 +1,30 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	"github.com/DeveloperPaul123/agenda/internal/secrets"
+)
+
+// apiKeySource returns where the API key of a provider is read from.
+func apiKeySource(config configs.ProviderConfig) secrets.Source {
+	return secrets.Source{
+		Command: config.APIKeyCommand,
+		File:    config.APIKeyFile,
+		Env:     config.EnvAPIKey,
+	}
+}
+
+// resolveAPIKey reads the API key of a provider and explains how to configure one if none is set.
+func resolveAPIKey(name string, config configs.ProviderConfig) (string, error) {
+	apiKey, err := apiKeySource(config).Resolve()
+	if errors.Is(err, secrets.ErrNotConfigured) {
+		return "", fmt.Errorf("no API key configured for %s, set api_key_command, api_key_file or env_api_key", name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get API key for %s: %w", name, err)
+	}
+	return apiKey, nil
+}
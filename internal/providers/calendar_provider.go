@@ -8,6 +8,39 @@ import (
 
 // CalendarProvider interface for different calendar services
 type CalendarProvider interface {
-	GetTodaysEvents(date time.Time) ([]models.CalendarEvent, error)
+	// GetEvents returns all events that start in the range [start, end).
+	GetEvents(start, end time.Time) ([]models.CalendarEvent, error)
 	GetName() string
 }
+
+// WritableProvider is implemented by providers that can change events. Read-only providers
+// do not implement it.
+type WritableProvider interface {
+	CalendarProvider
+	// CreateEvent creates the event in the calendar named by its CalendarName, or in the
+	// default calendar of the provider if it is empty. Returns the created event.
+	CreateEvent(event models.CalendarEvent) (models.CalendarEvent, error)
+	// UpdateEvent changes the title and time of an event returned by GetEvents to the ones of
+	// changed, only what differs is sent. Only the given occurrence of recurring events is changed.
+	// Returns the updated event.
+	UpdateEvent(event, changed models.CalendarEvent) (models.CalendarEvent, error)
+	// DeleteEvent deletes an event returned by GetEvents. Only the given occurrence of
+	// recurring events is deleted.
+	DeleteEvent(event models.CalendarEvent) error
+}
+
+// RSVPProvider is implemented by providers that can answer invitations.
+type RSVPProvider interface {
+	// RespondToEvent answers the invitation to an event returned by GetEvents with
+	// models.ResponseAccepted, models.ResponseDeclined or models.ResponseTentative and notifies the
+	// organizer. The comment is sent along with the response if it is not empty.
+	RespondToEvent(event models.CalendarEvent, response, comment string) error
+}
+
+// TaskProvider is implemented by providers that also manage tasks.
+type TaskProvider interface {
+	// GetTasks returns the tasks due or scheduled in the range [start, end) and the open tasks
+	// that were due before it, flagged as overdue. The events are the ones the provider returned
+	// for the range, tasks are scheduled by the events with their TaskID.
+	GetTasks(start, end time.Time, events []models.CalendarEvent) ([]models.Task, error)
+}
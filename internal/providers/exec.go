@@ -0,0 +1,206 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+	"github.com/DeveloperPaul123/agenda/internal/paths"
+	"github.com/DeveloperPaul123/agenda/internal/secrets"
+)
+
+// execProviderName is the name of the external command provider.
+const execProviderName = "exec"
+
+func init() {
+	Register(execProviderName, func(config configs.ProviderConfig, options Options) CalendarProvider {
+		return NewExecProvider(config, options)
+	})
+}
+
+// ExecProtocolVersion is the version of the protocol spoken with external commands.
+//
+// The command is run with the configured arguments and receives an execRequest as JSON on
+// stdin. The range is also available in the environment as AGENDA_START and AGENDA_END
+// (RFC 3339), next to AGENDA_PROTOCOL_VERSION and AGENDA_API_KEY if an API key is
+// configured. It prints an execResponse as JSON on stdout and exits with status 0. On
+// failure it exits with a non-zero status or sets error in the response; stderr is shown
+// to the user in both cases and logged with --verbose otherwise.
+const ExecProtocolVersion = 1
+
+// defaultExecTimeout is used if the provider configures no timeout.
+const defaultExecTimeout = 30 * time.Second
+
+// maxStderr limits how much of the stderr of a command is kept.
+const maxStderr = 64 * 1024
+
+// execRequest is written to the stdin of the command.
+type execRequest struct {
+	Version int `json:"version"`
+	// Method is "events", the only method of version 1.
+	Method   string            `json:"method"`
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end"`
+	Timezone string            `json:"timezone"`
+	Options  map[string]string `json:"options,omitempty"`
+}
+
+// execResponse is read from the stdout of the command.
+type execResponse struct {
+	Version int                    `json:"version"`
+	Events  []models.CalendarEvent `json:"events"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// ExecProvider implements CalendarProvider by running an external command, so providers
+// can be written in any language without changing agenda.
+type ExecProvider struct {
+	config  configs.ProviderConfig
+	options Options
+}
+
+// NewExecProvider creates a new instance of ExecProvider with the given configuration.
+func NewExecProvider(config configs.ProviderConfig, options Options) *ExecProvider {
+	return &ExecProvider{config: config, options: options}
+}
+
+// GetName returns the name of the provider.
+func (p *ExecProvider) GetName() string {
+	return instanceName(p.config, execProviderName)
+}
+
+// GetEvents runs the command for the given time range and returns the events it prints.
+func (p *ExecProvider) GetEvents(start, end time.Time) ([]models.CalendarEvent, error) {
+	if p.config.Command == "" {
+		return nil, errors.New("no command configured, set command in the exec provider configuration")
+	}
+	timeout := defaultExecTimeout
+	if p.config.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(p.config.Timeout); err != nil {
+			return nil, fmt.Errorf("invalid timeout %q: %w", p.config.Timeout, err)
+		}
+	}
+
+	// Commands need the IANA name to place events in the timezone, not "Local"
+	timezone, err := locationName(start.Location())
+	if err != nil {
+		return nil, err
+	}
+	request, err := json.Marshal(execRequest{
+		Version:  ExecProtocolVersion,
+		Method:   "events",
+		Start:    start,
+		End:      end,
+		Timezone: timezone,
+		Options:  p.config.Options,
+	})
+	if err != nil {
+		return nil, err
+	}
+	env, err := p.environment(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	command, err := paths.ExpandHome(p.config.Command)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, command, p.config.Args...)
+	cmd.Env = env
+	cmd.Stdin = bytes.NewReader(request)
+	// Children of the command may keep its output open after it was killed
+	cmd.WaitDelay = time.Second
+	var stdout bytes.Buffer
+	stderr := &limitedBuffer{limit: maxStderr}
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return nil, fmt.Errorf("%s timed out after %s%s", p.config.Command, timeout, stderr.details())
+	case err != nil:
+		return nil, fmt.Errorf("%s failed: %w%s", p.config.Command, err, stderr.details())
+	}
+	if p.options.Verbose && stderr.Len() > 0 {
+		for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+			log.Printf("%s: %s", p.config.Command, line)
+		}
+	}
+
+	var response execResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("%s printed an invalid response: %w", p.config.Command, err)
+	}
+	if response.Version != ExecProtocolVersion {
+		return nil, fmt.Errorf("%s uses protocol version %d, agenda supports version %d", p.config.Command, response.Version, ExecProtocolVersion)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s: %s%s", p.config.Command, response.Error, stderr.details())
+	}
+
+	events := make([]models.CalendarEvent, 0, len(response.Events))
+	for _, event := range response.Events {
+		if event.Status != models.StatusCancelled && !contains(p.config.CalendarsToIgnore, event.CalendarName) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// environment returns the environment of the command: the one of agenda and the time range,
+// protocol version and API key.
+func (p *ExecProvider) environment(start, end time.Time) ([]string, error) {
+	env := append(os.Environ(),
+		fmt.Sprintf("AGENDA_PROTOCOL_VERSION=%d", ExecProtocolVersion),
+		"AGENDA_START="+start.Format(time.RFC3339),
+		"AGENDA_END="+end.Format(time.RFC3339),
+	)
+
+	// The API key is optional, commands may handle authentication themselves
+	apiKey, err := apiKeySource(p.config).Resolve()
+	switch {
+	case errors.Is(err, secrets.ErrNotConfigured):
+		return env, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get API key for %s: %w", p.GetName(), err)
+	}
+	return append(env, "AGENDA_API_KEY="+apiKey), nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write implements io.Writer, it never fails so the command is not interrupted.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+// details formats the output for an error message, it is empty if there is none.
+func (b *limitedBuffer) details() string {
+	output := strings.TrimSpace(b.String())
+	if output == "" {
+		return ""
+	}
+	return ":\n" + output
+}
@@ -0,0 +1,110 @@
+package providers
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+)
+
+// buildPlugin builds the reference plugin in testdata/plugin.
+func buildPlugin(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "plugin")
+	if runtime.GOOS == "windows" {
+		path += ".exe"
+	}
+	output, err := exec.Command("go", "build", "-o", path, "./testdata/plugin").CombinedOutput()
+	if err != nil {
+		t.Skipf("failed to build the plugin: %v\n%s", err, output)
+	}
+	return path
+}
+
+func TestExecProvider(t *testing.T) {
+	plugin := buildPlugin(t)
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	provider := NewExecProvider(configs.ProviderConfig{
+		Command: plugin,
+		Options: map[string]string{"calendar": "Focus"},
+	}, Options{})
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events) != 2 || events[1].ID != "focus-2025-03-11" || events[1].CalendarName != "Focus" ||
+		!events[1].StartTime.Equal(start.Add(33*time.Hour)) {
+		t.Errorf("events = %+v", events)
+	}
+
+	tests := []struct {
+		mode    string
+		timeout string
+		want    string
+	}{
+		{mode: "fail", want: "could not reach the server"},
+		{mode: "error", want: "not authorized"},
+		{mode: "version", want: "protocol version 2"},
+		{mode: "hang", timeout: "200ms", want: "timed out after 200ms"},
+	}
+	for _, tt := range tests {
+		provider := NewExecProvider(configs.ProviderConfig{
+			Command: plugin,
+			Timeout: tt.timeout,
+			Options: map[string]string{"mode": tt.mode},
+		}, Options{})
+		_, err := provider.GetEvents(start, start.AddDate(0, 0, 1))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.mode, err, tt.want)
+		}
+	}
+}
+
+func TestExecProviderSystemTimeZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone not available: %v", err)
+	}
+	plugin := buildPlugin(t)
+	link := filepath.Join(t.TempDir(), "localtime")
+	if err := os.Symlink("/usr/share/zoneinfo/America/New_York", link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	defer func(path string) { localtimePath = path }(localtimePath)
+	localtimePath = link
+	t.Setenv("TZ", "")
+	os.Unsetenv("TZ")
+
+	// The plugin places the focus block at 09:00 in the timezone it receives
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	provider := NewExecProvider(configs.ProviderConfig{Command: plugin}, Options{})
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events) == 0 {
+		t.Fatal("GetEvents() returned no events")
+	}
+	if got := events[0].StartTime.In(newYork); got.Hour() != 9 {
+		t.Errorf("focus block starts at %s, want 09:00 in the system timezone", got.Format("15:04 MST"))
+	}
+}
+
+func TestExecProviderErrors(t *testing.T) {
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	for _, config := range []configs.ProviderConfig{
+		{},
+		{Command: "agenda-plugin-that-does-not-exist"},
+		{Command: "agenda-plugin-that-does-not-exist", Timeout: "soon"},
+	} {
+		if _, err := NewExecProvider(config, Options{}).GetEvents(start, start.AddDate(0, 0, 1)); err == nil {
+			t.Errorf("GetEvents() with %+v should fail", config)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package providers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// googleProviderName is the name of the Google Calendar provider.
+const googleProviderName = "google"
+
+// googleEventsScope is the OAuth scope needed to change events, e.g. to answer invitations.
+const googleEventsScope = "https://www.googleapis.com/auth/calendar.events"
+
+func init() {
+	Register(googleProviderName, func(config configs.ProviderConfig, options Options) CalendarProvider {
+		return NewGoogleProvider(config, options)
+	})
+}
+
+// googlePageSize is the number of items requested per page, the maximum the API allows is 250.
+const googlePageSize = 250
+
+// GoogleProvider implements CalendarProvider for the Google Calendar v3 API.
+type GoogleProvider struct {
+	config   configs.ProviderConfig
+	requests *requestBuilder
+}
+
+// googleCalendar represents an entry of the calendar list of the user.
+type googleCalendar struct {
+	ID              string `json:"id"`
+	Summary         string `json:"summary"`
+	SummaryOverride string `json:"summaryOverride"`
+	BackgroundColor string `json:"backgroundColor"`
+	AccessRole      string `json:"accessRole"`
+	Primary         bool   `json:"primary"`
+}
+
+// name returns the name the user gave the calendar, or its original name.
+func (c googleCalendar) name() string {
+	if c.SummaryOverride != "" {
+		return c.SummaryOverride
+	}
+	return c.Summary
+}
+
+// googleCalendarListResponse is a page of the calendar list.
+type googleCalendarListResponse struct {
+	Items         []googleCalendar `json:"items"`
+	NextPageToken string           `json:"nextPageToken"`
+}
+
+// googleEventTime is the start or end of an event. All-day events only have a date.
+type googleEventTime struct {
+	Date     string `json:"date"`
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// googleAttendee represents an attendee of an event.
+type googleAttendee struct {
+	Email          string `json:"email"`
+	DisplayName    string `json:"displayName"`
+	Self           bool   `json:"self"`
+	Organizer      bool   `json:"organizer"`
+	Resource       bool   `json:"resource"`
+	ResponseStatus string `json:"responseStatus"`
+}
+
+// googleEntryPoint is a way to join the conference of an event.
+type googleEntryPoint struct {
+	EntryPointType string `json:"entryPointType"`
+	URI            string `json:"uri"`
+}
+
+// googleConferenceData holds the conference, e.g. a Meet call, attached to an event.
+type googleConferenceData struct {
+	EntryPoints []googleEntryPoint `json:"entryPoints"`
+}
+
+// googleEvent represents an event in the Google Calendar API response.
+type googleEvent struct {
+	ID             string                `json:"id"`
+	Status         string                `json:"status"`
+	Summary        string                `json:"summary"`
+	Description    string                `json:"description"`
+	Location       string                `json:"location"`
+	Start          googleEventTime       `json:"start"`
+	End            googleEventTime       `json:"end"`
+	Attendees      []googleAttendee      `json:"attendees"`
+	HangoutLink    string                `json:"hangoutLink"`
+	ConferenceData *googleConferenceData `json:"conferenceData"`
+}
+
+// googleEventAttendees holds the attendees of an event. They are kept as raw objects so the fields
+// agenda does not know are sent back unchanged, as a patch replaces the whole list.
+type googleEventAttendees struct {
+	Attendees []map[string]any `json:"attendees"`
+}
+
+// googleEventsResponse is a page of the events of a calendar.
+type googleEventsResponse struct {
+	Items         []googleEvent `json:"items"`
+	NextPageToken string        `json:"nextPageToken"`
+}
+
+// NewGoogleProvider creates a new instance of GoogleProvider with the given configuration.
+func NewGoogleProvider(config configs.ProviderConfig, options Options) *GoogleProvider {
+	return &GoogleProvider{
+		config:   config,
+		requests: newRequestBuilder(instanceName(config, googleProviderName), config, options),
+	}
+}
+
+// GetName returns the name of the provider.
+func (g *GoogleProvider) GetName() string {
+	return instanceName(g.config, googleProviderName)
+}
+
+// getCalendars retrieves all pages of the calendar list of the user.
+func (g *GoogleProvider) getCalendars() ([]googleCalendar, error) {
+	var calendars []googleCalendar
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("maxResults", strconv.Itoa(googlePageSize))
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		req, err := g.requests.newRequest(http.MethodGet, "/users/me/calendarList", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var response googleCalendarListResponse
+		if err := g.requests.do(req, &response); err != nil {
+			return nil, err
+		}
+
+		calendars = append(calendars, response.Items...)
+		if response.NextPageToken == "" {
+			return calendars, nil
+		}
+		pageToken = response.NextPageToken
+	}
+}
+
+// getCalendarEvents retrieves all pages of the events of a calendar in [start, end).
+// Recurring events are expanded into single instances by the API.
+func (g *GoogleProvider) getCalendarEvents(calendarID string, start, end time.Time) ([]googleEvent, error) {
+	var events []googleEvent
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("singleEvents", "true")
+		query.Set("orderBy", "startTime")
+		query.Set("timeMin", start.Format(time.RFC3339))
+		query.Set("timeMax", end.Format(time.RFC3339))
+		query.Set("maxResults", strconv.Itoa(googlePageSize))
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		path := fmt.Sprintf("/calendars/%s/events", url.PathEscape(calendarID))
+		req, err := g.requests.newRequest(http.MethodGet, path, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var response googleEventsResponse
+		if err := g.requests.do(req, &response); err != nil {
+			return nil, err
+		}
+
+		events = append(events, response.Items...)
+		if response.NextPageToken == "" {
+			return events, nil
+		}
+		pageToken = response.NextPageToken
+	}
+}
+
+// GetEvents retrieves the events in the given time range from all calendars the user can read.
+// Returns a list of models.CalendarEvent or an error if a request fails.
+func (g *GoogleProvider) GetEvents(start, end time.Time) ([]models.CalendarEvent, error) {
+	calendars, err := g.getCalendars()
+	if err != nil {
+		return nil, err
+	}
+
+	// The primary calendar is named after the account, so it identifies the account of all calendars
+	accountID := ""
+	for _, calendar := range calendars {
+		if calendar.Primary {
+			accountID = calendar.ID
+		}
+	}
+
+	var events []models.CalendarEvent
+	for _, calendar := range calendars {
+		// Calendars with the freeBusyReader role do not expose any event details
+		if calendar.AccessRole == "freeBusyReader" || contains(g.config.CalendarsToIgnore, calendar.name()) {
+			continue
+		}
+
+		googleEvents, err := g.getCalendarEvents(calendar.ID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get events of calendar %s: %w", calendar.name(), err)
+		}
+
+		for _, ge := range googleEvents {
+			if ge.Status == models.StatusCancelled {
+				continue
+			}
+			event, err := ge.toCalendarEvent(start.Location())
+			if err != nil {
+				log.Printf("Warning: failed to parse event %s: %v", ge.ID, err)
+				continue
+			}
+			event.CalendarName = calendar.name()
+			event.CalendarID = calendar.ID
+			event.AccountID = accountID
+			// The ID of the primary calendar is the email address of the account
+			event.AccountName = accountID
+			event.Color = calendar.BackgroundColor
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// RespondToEvent answers the invitation to the event and notifies the organizer. Google uses the
+// same responses as agenda. Changing responses needs the calendar.events scope.
+func (g *GoogleProvider) RespondToEvent(event models.CalendarEvent, response, comment string) error {
+	path := fmt.Sprintf("/calendars/%s/events/%s", url.PathEscape(event.CalendarID), url.PathEscape(event.ID))
+	req, err := g.requests.newRequest(http.MethodGet, path, nil, nil)
+	if err != nil {
+		return err
+	}
+	var current googleEventAttendees
+	if err := g.requests.do(req, &current); err != nil {
+		return err
+	}
+
+	invited := false
+	for _, attendee := range current.Attendees {
+		if self, _ := attendee["self"].(bool); self {
+			if organizer, _ := attendee["organizer"].(bool); organizer {
+				return fmt.Errorf("you organize %s", event.Title)
+			}
+			attendee["responseStatus"] = response
+			if comment != "" {
+				attendee["comment"] = comment
+			}
+			invited = true
+		}
+	}
+	if !invited {
+		return fmt.Errorf("you are not invited to %s", event.Title)
+	}
+
+	query := url.Values{}
+	query.Set("sendUpdates", "all")
+	req, err = g.requests.newJSONRequest(http.MethodPatch, path, query, current)
+	if err != nil {
+		return err
+	}
+	// The default scope only allows reading, answering needs the events scope
+	err = g.requests.do(req, nil)
+	if isForbidden(err) {
+		return fmt.Errorf("%w, answering invitations needs the scope %s: add it to the scopes of the %s provider and run agenda auth %s again",
+			err, googleEventsScope, g.GetName(), g.GetName())
+	}
+	return err
+}
+
+// toCalendarEvent converts the event to the standard format. The dates of all-day events are
+// interpreted in the given location.
+func (ge googleEvent) toCalendarEvent(loc *time.Location) (models.CalendarEvent, error) {
+	startTime, allDay, err := ge.Start.parse(loc)
+	if err != nil {
+		return models.CalendarEvent{}, err
+	}
+	endTime, _, err := ge.End.parse(loc)
+	if err != nil {
+		return models.CalendarEvent{}, err
+	}
+
+	event := models.CalendarEvent{
+		ID:          ge.ID,
+		Title:       ge.Summary,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		Description: ge.Description,
+		Location:    ge.Location,
+		TimeZone:    ge.Start.TimeZone,
+		AllDay:      allDay,
+		Status:      ge.Status,
+		MeetingURL:  ge.meetingURL(),
+	}
+	for _, attendee := range ge.Attendees {
+		// The organizer is listed as an attendee who accepted, but has no invitation to answer
+		if attendee.Self && !attendee.Organizer {
+			event.ResponseStatus = attendee.ResponseStatus
+		}
+		// Meeting rooms are listed as attendees as well
+		if attendee.Resource {
+			continue
+		}
+		if attendee.DisplayName != "" {
+			event.Attendees = append(event.Attendees, attendee.DisplayName)
+		} else {
+			event.Attendees = append(event.Attendees, attendee.Email)
+		}
+	}
+	return event, nil
+}
+
+// meetingURL returns the video link of the conference of the event, if any.
+func (ge googleEvent) meetingURL() string {
+	if ge.ConferenceData != nil {
+		for _, entryPoint := range ge.ConferenceData.EntryPoints {
+			if entryPoint.EntryPointType == "video" {
+				return entryPoint.URI
+			}
+		}
+	}
+	return ge.HangoutLink
+}
+
+// parse returns the time and whether it is only a date, as used by all-day events.
+func (t googleEventTime) parse(loc *time.Location) (time.Time, bool, error) {
+	if t.DateTime != "" {
+		parsed, err := time.Parse(time.RFC3339, t.DateTime)
+		return parsed, false, err
+	}
+	parsed, err := time.ParseInLocation("2006-01-02", t.Date, loc)
+	return parsed, true, err
+}
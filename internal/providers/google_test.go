@@ -0,0 +1,222 @@
+package providers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// newGoogleStub serves a calendar list and events like the Google Calendar API, split into pages.
+func newGoogleStub(t *testing.T) *httptest.Server {
+	pages := map[string]map[string]string{
+		"/users/me/calendarList": {
+			"": `{"items": [
+				{"id": "me@example.com", "summary": "me@example.com", "summaryOverride": "Work", "backgroundColor": "#3b82f6", "accessRole": "owner", "primary": true}
+			], "nextPageToken": "cal-2"}`,
+			"cal-2": `{"items": [
+				{"id": "team@group.calendar.google.com", "summary": "Team", "backgroundColor": "#ef4444", "accessRole": "reader"},
+				{"id": "busy@example.com", "summary": "Busy", "accessRole": "freeBusyReader"},
+				{"id": "ignored@example.com", "summary": "Ignored", "accessRole": "reader"}
+			]}`,
+		},
+		"/calendars/me@example.com/events": {
+			"": `{"items": [
+				{"id": "standup", "status": "confirmed", "summary": "Standup",
+				 "start": {"dateTime": "2025-03-10T09:00:00+01:00", "timeZone": "Europe/Berlin"},
+				 "end": {"dateTime": "2025-03-10T09:30:00+01:00", "timeZone": "Europe/Berlin"},
+				 "attendees": [
+				   {"email": "me@example.com", "self": true, "responseStatus": "accepted"},
+				   {"email": "jane@example.com", "displayName": "Jane"},
+				   {"email": "room@resource.calendar.google.com", "resource": true}
+				 ],
+				 "hangoutLink": "https://meet.google.com/old-link",
+				 "conferenceData": {"entryPoints": [
+				   {"entryPointType": "phone", "uri": "tel:+1-555-0100"},
+				   {"entryPointType": "video", "uri": "https://meet.google.com/abc-defg-hij"}
+				 ]}}
+			], "nextPageToken": "events-2"}`,
+			"events-2": `{"items": [
+				{"id": "cancelled", "status": "cancelled"},
+				{"id": "broken", "status": "confirmed", "summary": "Broken",
+				 "start": {"dateTime": "next tuesday"}, "end": {"dateTime": "next tuesday"}},
+				{"id": "holiday", "status": "confirmed", "summary": "Holiday",
+				 "start": {"date": "2025-03-10"}, "end": {"date": "2025-03-11"}}
+			]}`,
+		},
+		"/calendars/team@group.calendar.google.com/events": {
+			"": `{"items": [
+				{"id": "review", "status": "tentative", "summary": "Review",
+				 "start": {"dateTime": "2025-03-10T14:00:00Z"}, "end": {"dateTime": "2025-03-10T15:00:00Z"},
+				 "attendees": [{"email": "me@example.com", "self": true, "responseStatus": "needsAction"}]},
+				{"id": "planning", "status": "confirmed", "summary": "Planning",
+				 "start": {"dateTime": "2025-03-10T16:00:00Z"}, "end": {"dateTime": "2025-03-10T17:00:00Z"},
+				 "attendees": [{"email": "me@example.com", "self": true, "organizer": true, "responseStatus": "accepted"}]}
+			]}`,
+		},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/users/me/calendarList" {
+			query := r.URL.Query()
+			if query.Get("singleEvents") != "true" || query.Get("timeMin") == "" || query.Get("timeMax") == "" {
+				t.Errorf("unexpected events query %s", r.URL.RawQuery)
+			}
+		}
+		page, ok := pages[r.URL.Path][r.URL.Query().Get("pageToken")]
+		if !ok {
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(page))
+	}))
+}
+
+func TestGoogleProviderGetEvents(t *testing.T) {
+	server := newGoogleStub(t)
+	defer server.Close()
+
+	provider := NewGoogleProvider(configs.ProviderConfig{
+		BaseURL:           server.URL,
+		Headers:           map[string]string{"Authorization": "Bearer test-token"},
+		CalendarsToIgnore: []string{"Ignored"},
+	}, Options{})
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, berlin)
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	if want := []string{"standup", "holiday", "review", "planning"}; !slices.Equal(ids, want) {
+		t.Fatalf("GetEvents() ids = %v, want %v", ids, want)
+	}
+
+	standup := events[0]
+	if !standup.StartTime.Equal(time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)) || standup.EndTime.Sub(standup.StartTime) != 30*time.Minute {
+		t.Errorf("standup times = %s-%s", standup.StartTime, standup.EndTime)
+	}
+	if standup.CalendarName != "Work" || standup.Color != "#3b82f6" || standup.AccountID != "me@example.com" {
+		t.Errorf("standup calendar = %q %q %q", standup.CalendarName, standup.Color, standup.AccountID)
+	}
+	if !slices.Equal(standup.Attendees, []string{"me@example.com", "Jane"}) {
+		t.Errorf("standup attendees = %v", standup.Attendees)
+	}
+	if standup.MeetingURL != "https://meet.google.com/abc-defg-hij" {
+		t.Errorf("standup meeting URL = %q", standup.MeetingURL)
+	}
+	if standup.ResponseStatus != models.ResponseAccepted || standup.TimeZone != "Europe/Berlin" {
+		t.Errorf("standup response = %q, timezone = %q", standup.ResponseStatus, standup.TimeZone)
+	}
+
+	holiday := events[1]
+	if !holiday.AllDay || !holiday.StartTime.Equal(start) || !holiday.EndTime.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("holiday = %+v, want an all-day event on %s", holiday, start)
+	}
+
+	review := events[2]
+	if review.Status != models.StatusTentative || review.ResponseStatus != models.ResponseNeedsAction {
+		t.Errorf("review status = %q, response = %q", review.Status, review.ResponseStatus)
+	}
+	if review.CalendarName != "Team" || review.AccountID != "me@example.com" {
+		t.Errorf("review calendar = %q, account = %q", review.CalendarName, review.AccountID)
+	}
+
+	// Events the user organizes have no invitation to answer, so rsvp --all skips them
+	if planning := events[3]; planning.ResponseStatus != "" {
+		t.Errorf("planning response = %q, want none for the organizer", planning.ResponseStatus)
+	}
+}
+
+func TestGoogleProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"message": "insufficient scopes"}})
+	}))
+	defer server.Close()
+
+	_, err := NewGoogleProvider(configs.ProviderConfig{BaseURL: server.URL}, Options{}).GetEvents(time.Now(), time.Now().Add(time.Hour))
+	if err == nil {
+		t.Error("GetEvents() should fail when the API returns an error")
+	}
+}
+
+func TestGoogleProviderRespondToEvent(t *testing.T) {
+	var patched googleEventAttendees
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calendars/team@group.calendar.google.com/events/review" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"id": "review", "attendees": [
+				{"email": "me@example.com", "self": true, "responseStatus": "needsAction"},
+				{"email": "jane@example.com", "organizer": true, "responseStatus": "accepted"}
+			]}`))
+		case http.MethodPatch:
+			if r.URL.Query().Get("sendUpdates") != "all" {
+				t.Errorf("organizer is not notified: %s", r.URL.RawQuery)
+			}
+			json.NewDecoder(r.Body).Decode(&patched)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGoogleProvider(configs.ProviderConfig{BaseURL: server.URL}, Options{})
+	event := models.CalendarEvent{ID: "review", CalendarID: "team@group.calendar.google.com", Title: "Review"}
+	if err := provider.RespondToEvent(event, models.ResponseDeclined, "On vacation"); err != nil {
+		t.Fatalf("RespondToEvent() error = %v", err)
+	}
+
+	if len(patched.Attendees) != 2 {
+		t.Fatalf("patched attendees = %v, want both attendees", patched.Attendees)
+	}
+	self, organizer := patched.Attendees[0], patched.Attendees[1]
+	if self["responseStatus"] != models.ResponseDeclined || self["comment"] != "On vacation" {
+		t.Errorf("self = %v, want declined with comment", self)
+	}
+	if organizer["organizer"] != true || organizer["responseStatus"] != models.ResponseAccepted {
+		t.Errorf("organizer = %v, want it unchanged", organizer)
+	}
+}
+
+func TestGoogleProviderRespondToEventReadOnlyScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"id": "review", "attendees": [{"email": "me@example.com", "self": true}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": 403, "message": "Request had insufficient authentication scopes."}}`))
+	}))
+	defer server.Close()
+
+	provider := NewGoogleProvider(configs.ProviderConfig{BaseURL: server.URL}, Options{})
+	event := models.CalendarEvent{ID: "review", CalendarID: "primary", Title: "Review"}
+	err := provider.RespondToEvent(event, models.ResponseAccepted, "")
+	if err == nil || !strings.Contains(err.Error(), googleEventsScope) || !strings.Contains(err.Error(), "agenda auth google") {
+		t.Errorf("RespondToEvent() error = %v, want it to name the missing scope", err)
+	}
+}
@@ -0,0 +1,365 @@
+package providers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	"github.com/DeveloperPaul123/agenda/internal/ical"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// graphProviderName is the name of the Microsoft Graph provider.
+const graphProviderName = "graph"
+
+// graphReadWriteScope is the OAuth scope needed to change events, e.g. to answer invitations.
+const graphReadWriteScope = "Calendars.ReadWrite"
+
+func init() {
+	Register(graphProviderName, func(config configs.ProviderConfig, options Options) CalendarProvider {
+		return NewGraphProvider(config, options)
+	})
+}
+
+// graphTimeLayout is the layout of dateTime values, Graph omits the offset and sends the zone separately.
+const graphTimeLayout = "2006-01-02T15:04:05.9999999"
+
+// GraphProvider implements CalendarProvider for Outlook and Exchange calendars via the Microsoft Graph API.
+type GraphProvider struct {
+	config   configs.ProviderConfig
+	requests *requestBuilder
+}
+
+// graphDateTime is a time of day in a named timezone.
+type graphDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// graphEmailAddress identifies an attendee or organizer.
+type graphEmailAddress struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// graphResponseStatus is the response of an attendee, or of the user for the event itself.
+type graphResponseStatus struct {
+	Response string `json:"response"`
+}
+
+// graphAttendee represents an attendee of an event.
+type graphAttendee struct {
+	Type         string            `json:"type"`
+	EmailAddress graphEmailAddress `json:"emailAddress"`
+}
+
+// graphOnlineMeeting holds the join link of a Teams or Skype meeting.
+type graphOnlineMeeting struct {
+	JoinURL string `json:"joinUrl"`
+}
+
+// graphLocation represents the location of an event.
+type graphLocation struct {
+	DisplayName string `json:"displayName"`
+}
+
+// graphBody is the body of an event, usually HTML.
+type graphBody struct {
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+}
+
+// graphCalendar represents a calendar of the user.
+type graphCalendar struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	HexColor string            `json:"hexColor"`
+	Owner    graphEmailAddress `json:"owner"`
+}
+
+// graphEvent represents an event in the Microsoft Graph API response.
+type graphEvent struct {
+	ID             string              `json:"id"`
+	Subject        string              `json:"subject"`
+	BodyPreview    string              `json:"bodyPreview"`
+	Body           graphBody           `json:"body"`
+	Start          graphDateTime       `json:"start"`
+	End            graphDateTime       `json:"end"`
+	IsAllDay       bool                `json:"isAllDay"`
+	IsCancelled    bool                `json:"isCancelled"`
+	IsOrganizer    bool                `json:"isOrganizer"`
+	ShowAs         string              `json:"showAs"`
+	ResponseStatus graphResponseStatus `json:"responseStatus"`
+	Location       graphLocation       `json:"location"`
+	Attendees      []graphAttendee     `json:"attendees"`
+	OnlineMeeting  *graphOnlineMeeting `json:"onlineMeeting"`
+	// OriginalStartTimeZone is the timezone the event was created in, an IANA or a Windows name.
+	OriginalStartTimeZone string `json:"originalStartTimeZone"`
+}
+
+// graphCalendarsResponse is a page of the calendars of the user.
+type graphCalendarsResponse struct {
+	Value    []graphCalendar `json:"value"`
+	NextLink string          `json:"@odata.nextLink"`
+}
+
+// graphEventsResponse is a page of the calendar view.
+type graphEventsResponse struct {
+	Value    []graphEvent `json:"value"`
+	NextLink string       `json:"@odata.nextLink"`
+}
+
+// graphResponses maps the responses of Graph to the invitation responses shared by all providers.
+var graphResponses = map[string]string{
+	"accepted":            models.ResponseAccepted,
+	"tentativelyAccepted": models.ResponseTentative,
+	"declined":            models.ResponseDeclined,
+	"notResponded":        models.ResponseNeedsAction,
+}
+
+// graphResponseActions maps the invitation responses shared by all providers to the actions of Graph
+// answering an invitation.
+var graphResponseActions = map[string]string{
+	models.ResponseAccepted:  "accept",
+	models.ResponseTentative: "tentativelyAccept",
+	models.ResponseDeclined:  "decline",
+}
+
+// graphResponseInput is the payload of the actions answering an invitation.
+type graphResponseInput struct {
+	Comment      string `json:"comment,omitempty"`
+	SendResponse bool   `json:"sendResponse"`
+}
+
+// NewGraphProvider creates a new instance of GraphProvider with the given configuration.
+func NewGraphProvider(config configs.ProviderConfig, options Options) *GraphProvider {
+	return &GraphProvider{
+		config:   config,
+		requests: newRequestBuilder(instanceName(config, graphProviderName), config, options),
+	}
+}
+
+// GetName returns the name of the provider.
+func (g *GraphProvider) GetName() string {
+	return instanceName(g.config, graphProviderName)
+}
+
+// get requests the path relative to the base URL with additional headers and decodes the response into out.
+func (g *GraphProvider) get(path string, query url.Values, out any, headers map[string]string) error {
+	req, err := g.requests.newRequest(http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return g.requests.do(req, out)
+}
+
+// nextPath returns the path of a @odata.nextLink relative to the base URL, including its query.
+func (g *GraphProvider) nextPath(nextLink string) (string, url.Values, error) {
+	next, err := url.Parse(nextLink)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid next link %s: %w", nextLink, err)
+	}
+	base, err := url.Parse(g.config.BaseURL)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid base URL %s: %w", g.config.BaseURL, err)
+	}
+	path := strings.TrimPrefix(next.Path, strings.TrimSuffix(base.Path, "/"))
+	return path, next.Query(), nil
+}
+
+// getCalendars retrieves all calendars of the user.
+func (g *GraphProvider) getCalendars() ([]graphCalendar, error) {
+	var calendars []graphCalendar
+	path, query := "/me/calendars", url.Values{}
+	for {
+		var response graphCalendarsResponse
+		if err := g.get(path, query, &response, nil); err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, response.Value...)
+		if response.NextLink == "" {
+			return calendars, nil
+		}
+
+		var err error
+		path, query, err = g.nextPath(response.NextLink)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getCalendarEvents retrieves all pages of the calendar view of a calendar in [start, end).
+// The calendar view expands recurring events into single occurrences.
+func (g *GraphProvider) getCalendarEvents(calendarID string, start, end time.Time) ([]graphEvent, error) {
+	// Ask for times in UTC so they can be parsed without a Windows timezone database
+	headers := map[string]string{"Prefer": `outlook.timezone="UTC"`}
+
+	path := fmt.Sprintf("/me/calendars/%s/calendarView", url.PathEscape(calendarID))
+	query := url.Values{}
+	query.Set("startDateTime", start.UTC().Format(time.RFC3339))
+	query.Set("endDateTime", end.UTC().Format(time.RFC3339))
+
+	var events []graphEvent
+	for {
+		var response graphEventsResponse
+		if err := g.get(path, query, &response, headers); err != nil {
+			return nil, err
+		}
+		events = append(events, response.Value...)
+		if response.NextLink == "" {
+			return events, nil
+		}
+
+		var err error
+		path, query, err = g.nextPath(response.NextLink)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// GetEvents retrieves the events in the given time range from all calendars of the user.
+// Returns a list of models.CalendarEvent or an error if a request fails.
+func (g *GraphProvider) GetEvents(start, end time.Time) ([]models.CalendarEvent, error) {
+	calendars, err := g.getCalendars()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []models.CalendarEvent
+	for _, calendar := range calendars {
+		if contains(g.config.CalendarsToIgnore, calendar.Name) {
+			continue
+		}
+
+		graphEvents, err := g.getCalendarEvents(calendar.ID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get events of calendar %s: %w", calendar.Name, err)
+		}
+
+		for _, ge := range graphEvents {
+			if ge.IsCancelled {
+				continue
+			}
+			event, err := ge.toCalendarEvent(start.Location())
+			if err != nil {
+				log.Printf("Warning: failed to parse event %s: %v", ge.ID, err)
+				continue
+			}
+			event.CalendarName = calendar.Name
+			event.CalendarID = calendar.ID
+			event.AccountID = calendar.Owner.Address
+			event.AccountName = calendar.Owner.Address
+			event.Color = calendar.HexColor
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// RespondToEvent answers the invitation to the event and notifies the organizer. Changing responses
+// needs the Calendars.ReadWrite permission.
+func (g *GraphProvider) RespondToEvent(event models.CalendarEvent, response, comment string) error {
+	action, exists := graphResponseActions[response]
+	if !exists {
+		return fmt.Errorf("unsupported response %s", response)
+	}
+
+	path := fmt.Sprintf("/me/calendars/%s/events/%s/%s", url.PathEscape(event.CalendarID), url.PathEscape(event.ID), action)
+	req, err := g.requests.newJSONRequest(http.MethodPost, path, nil, graphResponseInput{Comment: comment, SendResponse: true})
+	if err != nil {
+		return err
+	}
+	// The default scope only allows reading, answering needs the read-write scope
+	err = g.requests.do(req, nil)
+	if isForbidden(err) {
+		return fmt.Errorf("%w, answering invitations needs the scope %s: add it to the scopes of the %s provider and run agenda auth %s again",
+			err, graphReadWriteScope, g.GetName(), g.GetName())
+	}
+	return err
+}
+
+// toCalendarEvent converts the event to the standard format. All-day events start and end at
+// midnight in the given location rather than in UTC.
+func (ge graphEvent) toCalendarEvent(loc *time.Location) (models.CalendarEvent, error) {
+	startTime, err := ge.Start.parse(ge.IsAllDay, loc)
+	if err != nil {
+		return models.CalendarEvent{}, err
+	}
+	endTime, err := ge.End.parse(ge.IsAllDay, loc)
+	if err != nil {
+		return models.CalendarEvent{}, err
+	}
+
+	event := models.CalendarEvent{
+		ID:          ge.ID,
+		Title:       ge.Subject,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		Description: ge.Body.Content,
+		Location:    ge.Location.DisplayName,
+		AllDay:      ge.IsAllDay,
+		Status:      models.StatusConfirmed,
+	}
+	if event.Description == "" {
+		event.Description = ge.BodyPreview
+	}
+	if ge.ShowAs == "tentative" {
+		event.Status = models.StatusTentative
+	}
+	// The organizer has no response of their own, Graph reports "organizer" for them
+	if !ge.IsOrganizer {
+		event.ResponseStatus = graphResponses[ge.ResponseStatus.Response]
+	}
+	if ge.OnlineMeeting != nil {
+		event.MeetingURL = ge.OnlineMeeting.JoinURL
+	}
+	// Times are requested in UTC, so the timezone of the event is only known from where it was
+	// created. Windows names like "W. Europe Standard Time" are stored as their IANA name.
+	if ge.OriginalStartTimeZone != "" {
+		if zone, err := ical.LoadLocation(ge.OriginalStartTimeZone); err == nil {
+			event.TimeZone = zone.String()
+		}
+	}
+	for _, attendee := range ge.Attendees {
+		// Meeting rooms and equipment are attendees of type resource
+		if attendee.Type == "resource" {
+			continue
+		}
+		if attendee.EmailAddress.Name != "" {
+			event.Attendees = append(event.Attendees, attendee.EmailAddress.Name)
+		} else {
+			event.Attendees = append(event.Attendees, attendee.EmailAddress.Address)
+		}
+	}
+	return event, nil
+}
+
+// parse returns the time in UTC, as requested with the Prefer header. All-day events only use the
+// date, which is interpreted in the given location.
+func (t graphDateTime) parse(dateOnly bool, dateLoc *time.Location) (time.Time, error) {
+	if dateOnly {
+		date, _, _ := strings.Cut(t.DateTime, "T")
+		return time.ParseInLocation("2006-01-02", date, dateLoc)
+	}
+
+	loc := time.UTC
+	if t.TimeZone != "" && t.TimeZone != "UTC" {
+		// Fall back to the named timezone in case the Prefer header was ignored
+		zone, err := ical.LoadLocation(t.TimeZone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unsupported timezone %s: %w", t.TimeZone, err)
+		}
+		loc = zone
+	}
+	return time.ParseInLocation(graphTimeLayout, t.DateTime, loc)
+}
@@ -0,0 +1,179 @@
+package providers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// newGraphStub serves calendars and a calendar view like Microsoft Graph, with the
+// second page of events behind an absolute @odata.nextLink.
+func newGraphStub(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1.0/me/calendars":
+			w.Write([]byte(`{"value": [
+				{"id": "AAA=", "name": "Calendar", "hexColor": "#3b82f6", "owner": {"address": "me@contoso.com"}},
+				{"id": "BBB=", "name": "Birthdays", "owner": {"address": "me@contoso.com"}}
+			]}`))
+		case "/v1.0/me/calendars/AAA=/calendarView":
+			if prefer := r.Header.Get("Prefer"); !strings.Contains(prefer, "outlook.timezone") {
+				t.Errorf("Prefer = %q, want an outlook.timezone preference", prefer)
+			}
+			query := r.URL.Query()
+			if query.Get("startDateTime") != "2025-03-09T23:00:00Z" || query.Get("endDateTime") != "2025-03-10T23:00:00Z" {
+				t.Errorf("unexpected range %s", r.URL.RawQuery)
+			}
+
+			if query.Get("$skip") == "" {
+				w.Write([]byte(`{"value": [
+					{"id": "standup", "subject": "Standup", "bodyPreview": "Daily sync",
+					 "start": {"dateTime": "2025-03-10T08:00:00.0000000", "timeZone": "UTC"},
+					 "end": {"dateTime": "2025-03-10T08:30:00.0000000", "timeZone": "UTC"},
+					 "originalStartTimeZone": "Europe/Berlin",
+					 "responseStatus": {"response": "tentativelyAccepted"},
+					 "location": {"displayName": "Room 1"},
+					 "attendees": [
+					   {"type": "required", "emailAddress": {"name": "Jane", "address": "jane@contoso.com"}},
+					   {"type": "optional", "emailAddress": {"address": "bob@contoso.com"}},
+					   {"type": "resource", "emailAddress": {"name": "Room 1", "address": "room1@contoso.com"}}
+					 ],
+					 "onlineMeeting": {"joinUrl": "https://teams.microsoft.com/l/meetup-join/abc"}}
+				], "@odata.nextLink": "` + server.URL + `/v1.0/me/calendars/AAA=/calendarView?startDateTime=2025-03-09T23:00:00Z&endDateTime=2025-03-10T23:00:00Z&$skip=1"}`))
+				return
+			}
+			w.Write([]byte(`{"value": [
+				{"id": "cancelled", "subject": "Cancelled", "isCancelled": true,
+				 "start": {"dateTime": "2025-03-10T10:00:00.0000000", "timeZone": "UTC"},
+				 "end": {"dateTime": "2025-03-10T11:00:00.0000000", "timeZone": "UTC"}},
+				{"id": "broken", "subject": "Broken",
+				 "start": {"dateTime": "next tuesday", "timeZone": "UTC"},
+				 "end": {"dateTime": "next tuesday", "timeZone": "UTC"}},
+				{"id": "review", "subject": "Review",
+				 "start": {"dateTime": "2025-03-10T12:00:00.0000000", "timeZone": "W. Europe Standard Time"},
+				 "end": {"dateTime": "2025-03-10T13:00:00.0000000", "timeZone": "W. Europe Standard Time"}},
+				{"id": "offsite", "subject": "Offsite", "isAllDay": true, "isOrganizer": true, "showAs": "tentative",
+				 "responseStatus": {"response": "organizer"}, "originalStartTimeZone": "W. Europe Standard Time",
+				 "start": {"dateTime": "2025-03-10T00:00:00.0000000", "timeZone": "UTC"},
+				 "end": {"dateTime": "2025-03-11T00:00:00.0000000", "timeZone": "UTC"}}
+			]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestGraphProviderGetEvents(t *testing.T) {
+	server := newGraphStub(t)
+	defer server.Close()
+
+	provider := NewGraphProvider(configs.ProviderConfig{
+		BaseURL:           server.URL + "/v1.0",
+		Headers:           map[string]string{"Authorization": "Bearer test-token"},
+		CalendarsToIgnore: []string{"Birthdays"},
+	}, Options{})
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, berlin)
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("GetEvents() returned %d events, want 3: %+v", len(events), events)
+	}
+
+	standup := events[0]
+	if !standup.StartTime.Equal(time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)) || standup.EndTime.Sub(standup.StartTime) != 30*time.Minute {
+		t.Errorf("standup times = %s-%s", standup.StartTime, standup.EndTime)
+	}
+	if standup.ResponseStatus != models.ResponseTentative || standup.Status != models.StatusConfirmed {
+		t.Errorf("standup response = %q, status = %q", standup.ResponseStatus, standup.Status)
+	}
+	if standup.TimeZone != "Europe/Berlin" {
+		t.Errorf("standup timezone = %q", standup.TimeZone)
+	}
+	if standup.MeetingURL != "https://teams.microsoft.com/l/meetup-join/abc" {
+		t.Errorf("standup meeting URL = %q", standup.MeetingURL)
+	}
+	if !slices.Equal(standup.Attendees, []string{"Jane", "bob@contoso.com"}) {
+		t.Errorf("standup attendees = %v", standup.Attendees)
+	}
+	if standup.Description != "Daily sync" || standup.Location != "Room 1" {
+		t.Errorf("standup description = %q, location = %q", standup.Description, standup.Location)
+	}
+	if standup.CalendarName != "Calendar" || standup.AccountID != "me@contoso.com" || standup.Color != "#3b82f6" {
+		t.Errorf("standup calendar = %q %q %q", standup.CalendarName, standup.AccountID, standup.Color)
+	}
+
+	// Graph ignored the Prefer header and returned a Windows timezone name
+	review := events[1]
+	if !review.StartTime.Equal(time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("review start = %s, want 11:00 UTC", review.StartTime)
+	}
+
+	offsite := events[2]
+	if !offsite.AllDay || !offsite.StartTime.Equal(start) || !offsite.EndTime.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("offsite = %s-%s, want an all-day event on %s", offsite.StartTime, offsite.EndTime, start)
+	}
+	if offsite.ResponseStatus != "" || offsite.Status != models.StatusTentative || offsite.TimeZone != "Europe/Berlin" {
+		t.Errorf("offsite response = %q, status = %q, timezone = %q", offsite.ResponseStatus, offsite.Status, offsite.TimeZone)
+	}
+}
+
+func TestGraphProviderRespondToEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.URL.Path != "/v1.0/me/calendars/AAA=/events/standup/tentativelyAccept" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if string(body) != `{"comment":"Might be late","sendResponse":true}` {
+			t.Errorf("body = %s", body)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	provider := NewGraphProvider(configs.ProviderConfig{BaseURL: server.URL + "/v1.0"}, Options{})
+	event := models.CalendarEvent{ID: "standup", CalendarID: "AAA="}
+	if err := provider.RespondToEvent(event, models.ResponseTentative, "Might be late"); err != nil {
+		t.Errorf("RespondToEvent() error = %v", err)
+	}
+	if err := provider.RespondToEvent(event, models.ResponseNeedsAction, ""); err == nil {
+		t.Error("RespondToEvent() should fail for responses Graph cannot send")
+	}
+}
+
+func TestGraphProviderRespondToEventReadOnlyScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": "ErrorAccessDenied", "message": "Access is denied."}}`))
+	}))
+	defer server.Close()
+
+	provider := NewGraphProvider(configs.ProviderConfig{BaseURL: server.URL + "/v1.0"}, Options{})
+	event := models.CalendarEvent{ID: "standup", CalendarID: "AAA="}
+	err := provider.RespondToEvent(event, models.ResponseAccepted, "")
+	if err == nil || !strings.Contains(err.Error(), graphReadWriteScope) || !strings.Contains(err.Error(), "agenda auth graph") {
+		t.Errorf("RespondToEvent() error = %v, want it to name the missing scope", err)
+	}
+}
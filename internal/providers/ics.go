@@ -0,0 +1,261 @@
+package providers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	"github.com/DeveloperPaul123/agenda/internal/ical"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// icsProviderName is the name of the iCalendar feed provider.
+const icsProviderName = "ics"
+
+func init() {
+	Register(icsProviderName, func(config configs.ProviderConfig, options Options) CalendarProvider {
+		return NewICSProvider(config, options)
+	})
+}
+
+// ICSProvider implements CalendarProvider for iCalendar feeds published at a URL.
+type ICSProvider struct {
+	config   configs.ProviderConfig
+	requests *requestBuilder
+	cache    *feedCache
+}
+
+// feedCache stores downloaded feeds with their validators, so feeds that did not change are not
+// downloaded again and the last copy can be used while offline.
+type feedCache struct {
+	dir string
+}
+
+// feedValidators are the ETag and Last-Modified headers of a cached feed.
+type feedValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// NewICSProvider creates a new instance of ICSProvider with the given configuration.
+func NewICSProvider(config configs.ProviderConfig, options Options) *ICSProvider {
+	return &ICSProvider{
+		config:   config,
+		requests: newRequestBuilder(instanceName(config, icsProviderName), config, options),
+		cache:    &feedCache{dir: filepath.Join(configs.CacheDir(), "feeds")},
+	}
+}
+
+// GetName returns the name of the provider.
+func (p *ICSProvider) GetName() string {
+	return instanceName(p.config, icsProviderName)
+}
+
+// GetEvents retrieves the events in the given time range from all configured feeds,
+// with recurring events expanded.
+func (p *ICSProvider) GetEvents(start, end time.Time) ([]models.CalendarEvent, error) {
+	if len(p.config.URLs) == 0 {
+		return nil, errors.New("no feeds configured, add them to urls in the ics provider configuration")
+	}
+
+	var events []models.CalendarEvent
+	for _, feedURL := range p.config.URLs {
+		data, err := p.fetch(feedURL)
+		if err != nil {
+			return nil, err
+		}
+
+		calendar, err := ical.Parse(bytes.NewReader(data), start.Location())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse feed %s: %w", feedName(feedURL), err)
+		}
+		name := calendar.Name
+		if name == "" {
+			name = feedName(feedURL)
+		}
+		if contains(p.config.CalendarsToIgnore, name) {
+			continue
+		}
+
+		feedEvents, err := calendarEvents(calendar, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand feed %s: %w", name, err)
+		}
+		for i := range feedEvents {
+			feedEvents[i].CalendarName = name
+			feedEvents[i].CalendarID = feedID(feedURL)
+			feedEvents[i].Color = calendar.Color
+		}
+		events = append(events, feedEvents...)
+	}
+	return events, nil
+}
+
+// fetch downloads a feed, sending the validators of the cached copy so an unchanged feed is
+// answered with 304 Not Modified. The cached copy is used if the feed cannot be downloaded.
+func (p *ICSProvider) fetch(feedURL string) ([]byte, error) {
+	// webcal:// is the same feed over http, used to open subscriptions in calendar applications
+	requestURL := feedURL
+	if rest, found := strings.CutPrefix(requestURL, "webcal://"); found {
+		requestURL = "https://" + rest
+	}
+
+	req, err := p.requests.newRequestURL(http.MethodGet, requestURL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, validators, cacheErr := p.cache.load(feedURL)
+	if cacheErr == nil {
+		if validators.ETag != "" {
+			req.Header.Set("If-None-Match", validators.ETag)
+		}
+		if validators.LastModified != "" {
+			req.Header.Set("If-Modified-Since", validators.LastModified)
+		}
+	}
+
+	data, validators, err := p.download(req)
+	switch {
+	case err == nil && data == nil && cacheErr == nil:
+		return cached, nil
+	case err == nil && data != nil:
+		if err := p.cache.store(feedURL, data, validators); err != nil {
+			log.Printf("Warning: failed to cache feed %s: %v", feedName(feedURL), err)
+		}
+		return data, nil
+	case err != nil && cacheErr == nil:
+		log.Printf("Warning: failed to download feed %s, using the cached copy: %v", feedName(feedURL), err)
+		return cached, nil
+	case err == nil:
+		err = errors.New("feed was not modified but there is no cached copy")
+	}
+	return nil, fmt.Errorf("failed to download feed %s: %w", feedName(feedURL), err)
+}
+
+// download sends the request and returns the feed with its validators. It returns no data
+// if the server answered 304 Not Modified.
+func (p *ICSProvider) download(req *http.Request) ([]byte, feedValidators, error) {
+	resp, err := p.requests.client.Do(req)
+	if err != nil {
+		return nil, feedValidators{}, p.requests.redactError(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return nil, feedValidators{}, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, feedValidators{}, fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, feedValidators{}, fmt.Errorf("failed to read feed: %w", err)
+	}
+	return data, feedValidators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}, nil
+}
+
+// calendarEvents converts the occurrences of the calendar in [start, end) to the standard format.
+func calendarEvents(calendar *ical.Calendar, start, end time.Time) ([]models.CalendarEvent, error) {
+	occurrences, err := calendar.Occurrences(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []models.CalendarEvent
+	for _, occurrence := range occurrences {
+		status := strings.ToLower(occurrence.Status)
+		if status == models.StatusCancelled {
+			continue
+		}
+
+		event := models.CalendarEvent{
+			ID:          occurrence.UID,
+			Title:       occurrence.Summary,
+			StartTime:   occurrence.Start,
+			EndTime:     occurrence.End,
+			Description: occurrence.Description,
+			Location:    occurrence.Location,
+			TimeZone:    occurrence.TimeZone,
+			AllDay:      occurrence.AllDay,
+			Status:      status,
+		}
+		for _, attendee := range occurrence.Attendees {
+			if attendee.Resource {
+				continue
+			}
+			if attendee.Name != "" {
+				event.Attendees = append(event.Attendees, attendee.Name)
+			} else {
+				event.Attendees = append(event.Attendees, attendee.Email)
+			}
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// feedID returns a stable identifier of a feed that does not reveal its URL, which often contains a secret.
+func feedID(feedURL string) string {
+	sum := sha256.Sum256([]byte(feedURL))
+	return hex.EncodeToString(sum[:8])
+}
+
+// feedName names a feed by its host for messages and as a fallback calendar name.
+func feedName(feedURL string) string {
+	if parsed, err := url.Parse(feedURL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return feedID(feedURL)
+}
+
+// paths returns the files the feed and its validators are cached in.
+func (c *feedCache) paths(feedURL string) (string, string) {
+	base := filepath.Join(c.dir, feedID(feedURL))
+	return base + ".ics", base + ".json"
+}
+
+// load returns the cached copy of a feed and its validators.
+func (c *feedCache) load(feedURL string) ([]byte, feedValidators, error) {
+	dataPath, validatorsPath := c.paths(feedURL)
+	var validators feedValidators
+
+	data, err := os.ReadFile(dataPath)
+	if err != nil {
+		return nil, validators, err
+	}
+	if raw, err := os.ReadFile(validatorsPath); err == nil {
+		// Without validators the feed is simply downloaded again
+		json.Unmarshal(raw, &validators)
+	}
+	return data, validators, nil
+}
+
+// store caches a feed and its validators. Feeds may be private, so only the user can read them.
+func (c *feedCache) store(feedURL string, data []byte, validators feedValidators) error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	dataPath, validatorsPath := c.paths(feedURL)
+	if err := os.WriteFile(dataPath, data, 0600); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(validators)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(validatorsPath, raw, 0600)
+}
@@ -0,0 +1,107 @@
+package providers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+)
+
+const icsFeed = `BEGIN:VCALENDAR
+X-WR-CALNAME:Team
+X-APPLE-CALENDAR-COLOR:#3b82f6
+BEGIN:VEVENT
+UID:standup
+SUMMARY:Standup
+DTSTART:20250303T080000Z
+DTEND:20250303T081500Z
+RRULE:FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR
+ATTENDEE;CN=Jane:mailto:jane@example.com
+ATTENDEE;CUTYPE=ROOM:mailto:room1@example.com
+END:VEVENT
+BEGIN:VEVENT
+UID:cancelled
+SUMMARY:Cancelled
+STATUS:CANCELLED
+DTSTART:20250310T100000Z
+DTEND:20250310T110000Z
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestICSProviderGetEvents(t *testing.T) {
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(icsFeed))
+	}))
+	defer server.Close()
+	t.Setenv("FEED_TOKEN", "secret")
+
+	provider := NewICSProvider(configs.ProviderConfig{URLs: []string{server.URL + "/team.ics?token=${FEED_TOKEN}"}}, Options{})
+	provider.cache.dir = t.TempDir()
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		events, err := provider.GetEvents(start, start.AddDate(0, 0, 2))
+		if err != nil {
+			t.Fatalf("GetEvents() error = %v", err)
+		}
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events, got %d", len(events))
+		}
+
+		standup := events[1]
+		if !standup.StartTime.Equal(time.Date(2025, 3, 11, 8, 0, 0, 0, time.UTC)) || standup.Title != "Standup" {
+			t.Errorf("second event = %s at %s", standup.Title, standup.StartTime)
+		}
+		if standup.CalendarName != "Team" || standup.Color != "#3b82f6" || strings.Contains(standup.CalendarID, "secret") {
+			t.Errorf("calendar = %q %q %q", standup.CalendarName, standup.Color, standup.CalendarID)
+		}
+		if len(standup.Attendees) != 1 || standup.Attendees[0] != "Jane" {
+			t.Errorf("attendees = %v", standup.Attendees)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("requests = %d, not modified = %d, want the second request to be answered from the cache", requests, notModified)
+	}
+
+	// The cached copy is used while the feed is unreachable
+	server.Close()
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil || len(events) != 1 {
+		t.Errorf("GetEvents() offline = %d events, %v", len(events), err)
+	}
+}
+
+func TestICSProviderErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	provider := NewICSProvider(configs.ProviderConfig{URLs: []string{server.URL + "/missing.ics"}}, Options{})
+	provider.cache.dir = t.TempDir()
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	if _, err := provider.GetEvents(start, start.AddDate(0, 0, 1)); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("GetEvents() error = %v, want the status", err)
+	}
+
+	provider = NewICSProvider(configs.ProviderConfig{}, Options{})
+	if _, err := provider.GetEvents(start, start.AddDate(0, 0, 1)); err == nil {
+		t.Error("GetEvents() without feeds should fail")
+	}
+}
@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	"github.com/DeveloperPaul123/agenda/internal/manual"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+	"github.com/DeveloperPaul123/agenda/internal/paths"
+)
+
+// manualProviderName is the name of the manual events provider.
+const manualProviderName = "manual"
+
+func init() {
+	Register(manualProviderName, func(config configs.ProviderConfig, _ Options) CalendarProvider {
+		return NewManualProvider(config)
+	})
+}
+
+// ManualProvider implements CalendarProvider for events kept by hand in YAML or markdown files.
+type ManualProvider struct {
+	config configs.ProviderConfig
+}
+
+// NewManualProvider creates a new instance of ManualProvider with the given configuration.
+func NewManualProvider(config configs.ProviderConfig) *ManualProvider {
+	return &ManualProvider{config: config}
+}
+
+// GetName returns the name of the provider.
+func (p *ManualProvider) GetName() string {
+	return instanceName(p.config, manualProviderName)
+}
+
+// GetEvents retrieves the events in the given time range from all configured files.
+func (p *ManualProvider) GetEvents(start, end time.Time) ([]models.CalendarEvent, error) {
+	if len(p.config.Paths) == 0 {
+		return nil, errors.New("no event files configured, add them to paths in the manual provider configuration")
+	}
+
+	var events []manual.Event
+	for _, path := range p.config.Paths {
+		path, err := paths.ExpandHome(path)
+		if err != nil {
+			return nil, err
+		}
+		fileEvents, err := manual.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		for _, event := range fileEvents {
+			if !contains(p.config.CalendarsToIgnore, event.Calendar) {
+				events = append(events, event)
+			}
+		}
+	}
+
+	var calendarEvents []models.CalendarEvent
+	for _, occurrence := range manual.Occurrences(events, start, end) {
+		calendarEvents = append(calendarEvents, models.CalendarEvent{
+			ID:           fmt.Sprintf("%s@%s", occurrence.Title, occurrence.Start.Format(time.RFC3339)),
+			Title:        occurrence.Title,
+			StartTime:    occurrence.Start,
+			EndTime:      occurrence.End,
+			Description:  occurrence.Description,
+			Location:     occurrence.Location,
+			CalendarName: occurrence.Calendar,
+			CalendarID:   occurrence.Calendar,
+			AllDay:       occurrence.AllDay,
+		})
+	}
+	return calendarEvents, nil
+}
@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+)
+
+func TestManualProviderGetEvents(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"routines.md": "## Personal\n- Gym 18:00-19:00 weekdays\n## Chores\n- Laundry sat\n",
+		"events.yaml": "events:\n  - title: Dentist\n    date: 2025-03-10\n    time: \"09:30-10:15\"\n    location: Main Street 1\n",
+	})
+
+	provider := NewManualProvider(configs.ProviderConfig{
+		Paths:             []string{filepath.Join(dir, "routines.md"), filepath.Join(dir, "events.yaml")},
+		CalendarsToIgnore: []string{"Chores"},
+	})
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events) != 6 {
+		t.Fatalf("expected 6 events, got %+v", events)
+	}
+
+	dentist := events[0]
+	if dentist.Title != "Dentist" || dentist.CalendarName != "events" || dentist.Location != "Main Street 1" ||
+		dentist.EndTime.Sub(dentist.StartTime) != 45*time.Minute {
+		t.Errorf("dentist = %+v", dentist)
+	}
+	if gym := events[1]; gym.Title != "Gym" || gym.CalendarName != "Personal" || gym.StartTime.Hour() != 18 {
+		t.Errorf("gym = %+v", gym)
+	}
+
+	provider = NewManualProvider(configs.ProviderConfig{Paths: []string{filepath.Join(dir, "missing.md")}})
+	if _, err := provider.GetEvents(start, start.AddDate(0, 0, 1)); err == nil {
+		t.Error("GetEvents() with a missing file should fail")
+	}
+}
@@ -1,12 +1,9 @@
 package providers
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"os"
+	"net/url"
 	"slices"
 	"strings"
 	"time"
@@ -18,14 +15,27 @@ import (
 
 // MorgenProvider implements CalendarProvider for Morgen.so
 type MorgenProvider struct {
-	config configs.ProviderConfig
-	apiKey string
+	config   configs.ProviderConfig
+	requests *requestBuilder
+	// participants holds the participants of the events returned by GetEvents, which are needed
+	// to answer invitations but are not part of models.CalendarEvent.
+	participants map[morgenOccurrence]map[string]map[string]any
+}
+
+// morgenOccurrence identifies an occurrence of an event by its ID and the Unix time of its start.
+type morgenOccurrence struct {
+	id    string
+	start int64
 }
 
 // morgenCalenderRights represents the rights a user has on a calendar in Morgen
 // API response. It is used to determine if the user can read items in the calendar.
 type morgenCalenderRights struct {
 	CanRead bool `json:"mayReadItems"`
+	// CanWrite is set if the user may create and change events in the calendar.
+	CanWrite bool `json:"mayWriteAll"`
+	// CanRSVP is set if the user may answer invitations to events in the calendar.
+	CanRSVP bool `json:"mayRSVP"`
 }
 
 // morgenCalendar represents a calendar in the Morgen API response.
@@ -35,13 +45,31 @@ type morgenCalendar struct {
 	CalenderRights morgenCalenderRights `json:"myRights"`
 	Id             string               `json:"id"`
 	Color          string               `json:"color"`
+	// accountName is the name of the account the calendar belongs to, it is not part of the calendar itself.
+	accountName string
+}
+
+// morgenAccount represents a connected calendar account in the Morgen API response.
+type morgenAccount struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"providerUserDisplayName"`
+	// UserID is the user of the calendar service, usually an email address.
+	UserID string `json:"providerUserId"`
+}
+
+// name returns the display name of the account, or the user it belongs to.
+func (a morgenAccount) name() string {
+	if a.DisplayName != "" {
+		return a.DisplayName
+	}
+	return a.UserID
 }
 
 // morgenCalendarsResponseData represents the response structure from Morgen API
 // for the list of calendars. It contains a slice of morgenCalendar objects.
 type morgenCalendarsResponseData struct {
 	Calendars []morgenCalendar `json:"calendars"`
-	// TODO: Accounts?
+	Accounts  []morgenAccount  `json:"accounts"`
 }
 
 // morgenCalendarsResponse represents the response structure from Morgen API
@@ -50,9 +78,16 @@ type morgenCalendarsResponse struct {
 	Data morgenCalendarsResponseData `json:"data"`
 }
 
+// morgenVirtualLocation represents a virtual room, e.g. a video call, attached to an event in the Morgen API response.
+type morgenVirtualLocation struct {
+	Name string `json:"name"`
+	URI  string `json:"uri"`
+}
+
 // morgenEvent represents the response structure from Morgen API
 type morgenEvent struct {
 	ID          string `json:"id"`
+	CalendarID  string `json:"calendarId"`
 	Title       string `json:"title"`
 	StartTime   string `json:"start"`
 	Duration    string `json:"duration"`
@@ -60,6 +95,18 @@ type morgenEvent struct {
 	EndTime     string `json:"end"`
 	Description string `json:"description"`
 	Location    string `json:"location"`
+	// VirtualLocations are keyed by an id and hold links to online meeting rooms
+	VirtualLocations map[string]morgenVirtualLocation `json:"virtualLocations"`
+	// Metadata links events created by Morgen, e.g. time blocks of scheduled tasks.
+	Metadata morgenEventMetadata `json:"morgen.so:metadata"`
+	// Participants are keyed by an id. They are kept as raw objects so they can be sent back
+	// unchanged when answering an invitation.
+	Participants map[string]map[string]any `json:"participants"`
+}
+
+// morgenEventMetadata holds the Morgen specific data of an event.
+type morgenEventMetadata struct {
+	TaskID string `json:"taskId"`
 }
 
 // morgenEventsResponseData represents the response structure from Morgen API
@@ -78,166 +125,86 @@ type morgenEventsResponse struct {
 // It is used to identify the provider in the application.
 const morgenProviderName = "morgen"
 
+// morgenLocalTimeLayout is the layout of local date-times in the Morgen API, the timezone is a separate field.
+const morgenLocalTimeLayout = "2006-01-02T15:04:05"
+
+func init() {
+	Register(morgenProviderName, func(config configs.ProviderConfig, options Options) CalendarProvider {
+		return NewMorgenProvider(config, options)
+	})
+}
+
 // contains checks if a string is present in a slice of strings.
 func contains(list []string, target string) bool {
 	return slices.Contains(list, target)
 }
 
-// ProviderName returns the name of the Morgen provider.
-func ProviderName() string {
-	return morgenProviderName
+// virtualRoomURL returns the link of the first virtual room of an event that is a web link.
+func virtualRoomURL(locations map[string]morgenVirtualLocation) string {
+	keys := make([]string, 0, len(locations))
+	for key := range locations {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if uri := locations[key].URI; strings.HasPrefix(uri, "https://") {
+			return uri
+		}
+	}
+	return ""
 }
 
 // NewMorgenProvider creates a new instance of MorgenProvider with the given configuration.
-func NewMorgenProvider(config configs.ProviderConfig) *MorgenProvider {
+func NewMorgenProvider(config configs.ProviderConfig, options Options) *MorgenProvider {
 	return &MorgenProvider{
-		config: config,
-		apiKey: os.Getenv(config.EnvAPIKey),
+		config:   config,
+		requests: newRequestBuilder(instanceName(config, morgenProviderName), config, options),
 	}
 }
 
 // GetName returns the name of the provider.
 func (m *MorgenProvider) GetName() string {
-	return ProviderName()
+	return instanceName(m.config, morgenProviderName)
 }
 
-// getApiKey retrieves the API key from the environment variable specified in the provider configuration.
-// If the API key is not set, it returns an error.
-func (m *MorgenProvider) getApiKey() (string, error) {
-	if m.apiKey == "" {
-		return "", fmt.Errorf("API key not found in environment variable %s", m.config.EnvAPIKey)
-	}
-
-	return m.apiKey, nil
-}
-
-// getCalendars retrieves the list of calendars from the Morgen API along with account info but we currently only use the calender data response.
+// getCalendars retrieves the list of calendars from the Morgen API along with account info, which is only used for the account names.
 // Returns a list of morgenCalendar objects or an error if the request fails.
 func (m *MorgenProvider) getCalendars() ([]morgenCalendar, error) {
-	apiKey, err := m.getApiKey()
+	req, err := m.requests.newRequest(http.MethodGet, "/calendars/list", nil, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build URL with date range
-	url := fmt.Sprintf("%s/calendars/list",
-		m.config.BaseURL)
-
-	// Create request
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add headers
-	for key, value := range m.config.Headers {
-		if key == "Authorization" {
-			value = strings.Replace(value, "{API_KEY}", apiKey, 1)
-		}
-		req.Header.Set(key, value)
-	}
-
-	// Make request
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+	var responseData morgenCalendarsResponse
+	if err := m.requests.do(req, &responseData); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	accountNames := make(map[string]string)
+	for _, account := range responseData.Data.Accounts {
+		accountNames[account.ID] = account.name()
 	}
-
-	// Parse response
-	var responseData morgenCalendarsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&responseData); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	calendars := responseData.Data.Calendars
+	for i := range calendars {
+		calendars[i].accountName = accountNames[calendars[i].AccountId]
 	}
-
-	return responseData.Data.Calendars, nil
+	return calendars, nil
 }
 
-// GetTodaysEvents retrieves today's events from the Morgen API.
-// It uses the provided date to determine the start of the day that it will use to pull events for.
+// GetEvents retrieves the events in the given time range from the Morgen API.
 // Returns a list of models.CalendarEvent or an error if the request fails.
-func (m *MorgenProvider) GetTodaysEvents(useDate time.Time) ([]models.CalendarEvent, error) {
-	apiKey, err := m.getApiKey()
-	if err != nil {
-		return nil, err
-	}
-
-	calendars, err := m.getCalendars()
+func (m *MorgenProvider) GetEvents(start, end time.Time) ([]models.CalendarEvent, error) {
+	morgenEvents, calendarsById, err := m.listEvents(start, end)
 	if err != nil {
 		return nil, err
 	}
 
-	accountCalendarMap := make(map[string][]string)
-	for i := range calendars {
-		cal := calendars[i]
-		// Only include calendars that the user has read access to and are not in the ignore list
-		if cal.CalenderRights.CanRead && !contains(m.config.CalendarsToIgnore, cal.Name) {
-			accountCalendarMap[cal.AccountId] = append(accountCalendarMap[cal.AccountId], cal.Id)
-		}
-	}
-
-	// Get today's date range
-	startOfDay := time.Date(useDate.Year(), useDate.Month(), useDate.Day(), 0, 0, 0, 0, useDate.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-
-	// Build URL with date range
-	url := fmt.Sprintf("%s/events/list",
-		m.config.BaseURL)
-
-	// Loop through key values and create requests for each one and run in parallel
-	var morgenEvents []morgenEvent
-	for accountId, calendarIds := range accountCalendarMap {
-		// Create request
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-		query := req.URL.Query()
-		query.Set("start", startOfDay.Format(time.RFC3339))
-		query.Set("end", endOfDay.Format(time.RFC3339))
-
-		query.Set("accountId", accountId)
-		query.Set("calendarIds", strings.Join(calendarIds, ","))
-		req.URL.RawQuery = query.Encode()
-
-		// Add headers
-		for key, value := range m.config.Headers {
-			if key == "Authorization" {
-				value = strings.Replace(value, "{API_KEY}", apiKey, 1)
-			}
-			req.Header.Set(key, value)
-		}
-
-		// Make request
-		client := &http.Client{Timeout: 30 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to make request: %w", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-		}
-
-		// Parse response
-		var response morgenEventsResponse
-		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-			return nil, fmt.Errorf("failed to decode response: %w", err)
-		}
-		morgenEvents = append(morgenEvents, response.Data.Events...)
-	}
-
 	// Convert to standard format
 	var events []models.CalendarEvent
 	for _, me := range morgenEvents {
+		calendar := calendarsById[me.CalendarID]
+
 		loc, err := time.LoadLocation(me.TimeZone)
 		if err != nil {
 			log.Printf("Warning: failed to load timezone %s: %v", me.TimeZone, err)
@@ -245,7 +212,7 @@ func (m *MorgenProvider) GetTodaysEvents(useDate time.Time) ([]models.CalendarEv
 		}
 
 		// Response times do not have the timezone, that is a separate field
-		startTime, err := time.ParseInLocation("2006-01-02T15:04:05", me.StartTime, loc)
+		startTime, err := time.ParseInLocation(morgenLocalTimeLayout, me.StartTime, loc)
 		if err != nil {
 			log.Printf("Warning: failed to parse start time %s: %v", me.StartTime, err)
 			continue
@@ -263,16 +230,73 @@ func (m *MorgenProvider) GetTodaysEvents(useDate time.Time) ([]models.CalendarEv
 			continue
 		}
 
+		if m.participants == nil {
+			m.participants = make(map[morgenOccurrence]map[string]map[string]any)
+		}
+		m.participants[morgenOccurrence{me.ID, startTime.Unix()}] = me.Participants
+
 		events = append(events, models.CalendarEvent{
-			ID:    me.ID,
-			Title: me.Title,
-			// Convert start and end times to the correct timezone
-			StartTime:   startTime.In(time.Local),
-			EndTime:     endTime.In(time.Local),
-			Description: me.Description,
-			Location:    me.Location,
+			ID:           me.ID,
+			Title:        me.Title,
+			StartTime:    startTime,
+			EndTime:      endTime,
+			TimeZone:     me.TimeZone,
+			Description:  me.Description,
+			Location:     me.Location,
+			CalendarName: calendar.Name,
+			CalendarID:   calendar.Id,
+			AccountID:    calendar.AccountId,
+			AccountName:  calendar.accountName,
+			Color:        calendar.Color,
+			MeetingURL:   virtualRoomURL(me.VirtualLocations),
+			// Only invitations have a participant for the owner of the account
+			ResponseStatus: morgenResponses[participationStatus(me.Participants)],
+			TaskID:         me.Metadata.TaskID,
 		})
 	}
 
 	return events, nil
 }
+
+// listEvents retrieves the events in the given time range of all readable calendars that are not ignored.
+// Returns the events and the calendars they belong to by id.
+func (m *MorgenProvider) listEvents(start, end time.Time) ([]morgenEvent, map[string]morgenCalendar, error) {
+	calendars, err := m.getCalendars()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accountCalendarMap := make(map[string][]string)
+	calendarsById := make(map[string]morgenCalendar)
+	for i := range calendars {
+		cal := calendars[i]
+		// Only include calendars that the user has read access to and are not in the ignore list
+		if cal.CalenderRights.CanRead && !contains(m.config.CalendarsToIgnore, cal.Name) {
+			accountCalendarMap[cal.AccountId] = append(accountCalendarMap[cal.AccountId], cal.Id)
+			calendarsById[cal.Id] = cal
+		}
+	}
+
+	// Request the events of each account with the date range
+	var morgenEvents []morgenEvent
+	for accountId, calendarIds := range accountCalendarMap {
+		query := url.Values{}
+		query.Set("start", start.Format(time.RFC3339))
+		query.Set("end", end.Format(time.RFC3339))
+		query.Set("accountId", accountId)
+		query.Set("calendarIds", strings.Join(calendarIds, ","))
+
+		req, err := m.requests.newRequest(http.MethodGet, "/events/list", query, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var response morgenEventsResponse
+		if err := m.requests.do(req, &response); err != nil {
+			return nil, nil, err
+		}
+		morgenEvents = append(morgenEvents, response.Data.Events...)
+	}
+
+	return morgenEvents, calendarsById, nil
+}
@@ -0,0 +1,21 @@
+package providers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMorgenGetEventsAccountName(t *testing.T) {
+	server := newMorgenStub(t)
+	defer server.Close()
+	provider := newTestMorgenProvider(t, server)
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil || len(events) == 0 {
+		t.Fatalf("GetEvents() = %v, %v", events, err)
+	}
+	if events[0].AccountID != "acc" || events[0].AccountName != "me@example.com" {
+		t.Errorf("account = %q, %q, want acc named me@example.com", events[0].AccountID, events[0].AccountName)
+	}
+}
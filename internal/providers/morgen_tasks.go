@@ -0,0 +1,155 @@
+package providers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// morgenTaskLimit is the number of tasks requested from the Morgen API at once, the most it returns.
+const morgenTaskLimit = 100
+
+// morgenTaskCompleted is the progress of completed tasks in the Morgen API response.
+const morgenTaskCompleted = "completed"
+
+// morgenTask represents a task in the Morgen API response.
+type morgenTask struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Due is a local date-time like the start of events, in TimeZone if it is set.
+	Due      string `json:"due"`
+	TimeZone string `json:"timeZone"`
+	// Progress is needs-action, in-process or completed.
+	Progress string `json:"progress"`
+	Priority int    `json:"priority"`
+}
+
+// morgenTasksResponseData represents the response structure from Morgen API
+// for the list of tasks. It contains a slice of morgenTask objects.
+type morgenTasksResponseData struct {
+	Tasks []morgenTask `json:"tasks"`
+}
+
+// morgenTasksResponse represents the response structure from Morgen API
+// for the list of tasks. It contains a data field with morgenTasksResponseData.
+type morgenTasksResponse struct {
+	Data morgenTasksResponseData `json:"data"`
+}
+
+// parseMorgenTime parses a local date-time of the Morgen API in the given timezone, or in
+// fallback if the timezone is empty.
+func parseMorgenTime(value, timeZone string, fallback *time.Location) (time.Time, error) {
+	loc := fallback
+	if timeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timeZone); err != nil {
+			return time.Time{}, fmt.Errorf("failed to load timezone %s: %w", timeZone, err)
+		}
+	}
+	parsed, err := time.ParseInLocation(morgenLocalTimeLayout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse time %s: %w", value, err)
+	}
+	return parsed, nil
+}
+
+// listTasks retrieves all tasks from the Morgen API, requesting them in pages of morgenTaskLimit.
+func (m *MorgenProvider) listTasks() ([]morgenTask, error) {
+	var tasks []morgenTask
+	seen := make(map[string]bool)
+	for {
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(morgenTaskLimit))
+		query.Set("offset", strconv.Itoa(len(tasks)))
+		req, err := m.requests.newRequest(http.MethodGet, "/tasks/list", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var response morgenTasksResponse
+		if err := m.requests.do(req, &response); err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, mt := range response.Data.Tasks {
+			if !seen[mt.ID] {
+				seen[mt.ID] = true
+				tasks = append(tasks, mt)
+				added++
+			}
+		}
+		// A short page is the last one, a page without new tasks means the offset is not supported
+		if len(response.Data.Tasks) < morgenTaskLimit || added == 0 {
+			return tasks, nil
+		}
+	}
+}
+
+// GetTasks retrieves the tasks due or scheduled in the given time range from the Morgen API,
+// together with the open tasks that were due before it. Overdue tasks come first, the others
+// are sorted by when they are scheduled or due.
+func (m *MorgenProvider) GetTasks(start, end time.Time, events []models.CalendarEvent) ([]models.Task, error) {
+	morgenTasks, err := m.listTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	// Scheduled tasks are time blocks in the calendar that link to the task
+	scheduled := make(map[string]time.Time)
+	for _, event := range events {
+		if event.TaskID == "" {
+			continue
+		}
+		if previous, exists := scheduled[event.TaskID]; !exists || event.StartTime.Before(previous) {
+			scheduled[event.TaskID] = event.StartTime
+		}
+	}
+
+	var tasks []models.Task
+	for _, mt := range morgenTasks {
+		task := models.Task{
+			ID:          mt.ID,
+			Title:       mt.Title,
+			Description: mt.Description,
+			Scheduled:   scheduled[mt.ID],
+			Completed:   mt.Progress == morgenTaskCompleted,
+			Priority:    mt.Priority,
+		}
+		if mt.Due != "" {
+			if task.Due, err = parseMorgenTime(mt.Due, mt.TimeZone, start.Location()); err != nil {
+				log.Printf("Warning: %v", err)
+				continue
+			}
+		}
+
+		dueInRange := !task.Due.IsZero() && !task.Due.Before(start) && task.Due.Before(end)
+		task.Overdue = !task.Completed && !task.Due.IsZero() && task.Due.Before(start)
+		if dueInRange || task.Overdue || !task.Scheduled.IsZero() {
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Overdue != tasks[j].Overdue {
+			return tasks[i].Overdue
+		}
+		return taskTime(tasks[i]).Before(taskTime(tasks[j]))
+	})
+	return tasks, nil
+}
+
+// taskTime returns when a task is scheduled, or when it is due if it is not scheduled.
+func taskTime(task models.Task) time.Time {
+	if !task.Scheduled.IsZero() {
+		return task.Scheduled
+	}
+	return task.Due
+}
@@ -0,0 +1,146 @@
+package providers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+)
+
+// newMorgenStub serves a calendar with a scheduled task and a task list like the Morgen API.
+func newMorgenStub(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "ApiKey test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/calendars/list":
+			w.Write([]byte(`{"data": {"calendars": [
+				{"id": "cal", "accountId": "acc", "name": "Work", "myRights": {"mayReadItems": true, "mayWriteAll": true, "mayRSVP": true}},
+				{"id": "shared", "accountId": "acc", "name": "Shared", "myRights": {"mayReadItems": true}}
+			], "accounts": [
+				{"id": "acc", "providerUserId": "me@example.com"}
+			]}}`))
+		case "/events/list":
+			w.Write([]byte(`{"data": {"events": [
+				{"id": "e1", "calendarId": "cal", "title": "Standup", "start": "2025-03-10T09:00:00", "duration": "PT15M", "timeZone": "UTC",
+				 "participants": {
+				   "me": {"email": "me@example.com", "accountOwner": true, "participationStatus": "needs-action"},
+				   "jane": {"email": "jane@example.com", "roles": {"owner": true}, "participationStatus": "accepted"}
+				 }},
+				{"id": "e2", "calendarId": "cal", "title": "Write report", "start": "2025-03-10T14:00:00", "duration": "PT1H", "timeZone": "UTC",
+				 "morgen.so:metadata": {"taskId": "report"}}
+			]}}`))
+		case "/events/create":
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != http.MethodPost || !strings.Contains(string(body), `"calendarId":"cal"`) {
+				t.Errorf("unexpected create request %s %s", r.Method, body)
+			}
+			w.Write([]byte(`{"data": {"event": {"id": "new-event"}}}`))
+		case "/events/update", "/events/delete":
+			body, _ := io.ReadAll(r.Body)
+			if r.URL.Query().Get("seriesUpdateMode") != "single" || !strings.Contains(string(body), `"id":"e1"`) {
+				t.Errorf("unexpected change request %s %s", r.URL, body)
+			}
+			w.Write([]byte(`{}`))
+		case "/tasks/list":
+			w.Write([]byte(`{"data": {"tasks": [
+				{"id": "report", "title": "Write report", "progress": "needs-action"},
+				{"id": "taxes", "title": "File taxes", "due": "2025-03-07T23:59:00", "timeZone": "UTC", "progress": "needs-action"},
+				{"id": "old", "title": "Old and done", "due": "2025-03-07T23:59:00", "progress": "completed"},
+				{"id": "groceries", "title": "Groceries", "due": "2025-03-10T12:00:00", "progress": "completed", "priority": 3},
+				{"id": "later", "title": "Later", "due": "2025-03-20T12:00:00", "progress": "needs-action"},
+				{"id": "someday", "title": "Someday", "progress": "needs-action"}
+			]}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestMorgenGetTasks(t *testing.T) {
+	server := newMorgenStub(t)
+	defer server.Close()
+	provider := newTestMorgenProvider(t, server)
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events) != 2 || events[1].TaskID != "report" {
+		t.Fatalf("events = %+v, want the time block of the report", events)
+	}
+
+	// The scheduled tasks are found in the events, they are not requested again
+	server.Config.Handler = tasksOnly(t, server.Config.Handler)
+	tasks, err := provider.GetTasks(start, start.AddDate(0, 0, 1), events)
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("tasks = %v, want File taxes, Groceries and Write report", titles)
+	}
+	if taxes := tasks[0]; taxes.ID != "taxes" || !taxes.Overdue {
+		t.Errorf("first task = %+v, want the overdue taxes", taxes)
+	}
+	if groceries := tasks[1]; groceries.ID != "groceries" || !groceries.Completed || groceries.Overdue || groceries.Priority != 3 {
+		t.Errorf("second task = %+v", groceries)
+	}
+	if report := tasks[2]; report.ID != "report" || !report.Scheduled.Equal(start.Add(14*time.Hour)) {
+		t.Errorf("third task = %+v, want the report scheduled at 14:00", report)
+	}
+}
+
+// tasksOnly fails the test on any request of the handler other than the task list.
+func tasksOnly(t *testing.T, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tasks/list" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func TestMorgenGetTasksPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var tasks []string
+		for i := offset; i < min(offset+morgenTaskLimit, 150); i++ {
+			tasks = append(tasks, fmt.Sprintf(`{"id": "t%d", "title": "Task %d", "due": "2025-03-10T12:00:00", "progress": "needs-action"}`, i, i))
+		}
+		fmt.Fprintf(w, `{"data": {"tasks": [%s]}}`, strings.Join(tasks, ","))
+	}))
+	defer server.Close()
+	provider := newTestMorgenProvider(t, server)
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	tasks, err := provider.GetTasks(start, start.AddDate(0, 0, 1), nil)
+	if err != nil || len(tasks) != 150 {
+		t.Fatalf("GetTasks() = %d tasks, %v, want 150", len(tasks), err)
+	}
+}
+
+// newTestMorgenProvider creates a Morgen provider for the stub server.
+func newTestMorgenProvider(t *testing.T, server *httptest.Server) *MorgenProvider {
+	t.Setenv("TEST_MORGEN_KEY", "test-key")
+	return NewMorgenProvider(configs.ProviderConfig{
+		BaseURL:   server.URL,
+		Headers:   map[string]string{"Authorization": "ApiKey {API_KEY}"},
+		EnvAPIKey: "TEST_MORGEN_KEY",
+	}, Options{})
+}
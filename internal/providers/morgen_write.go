@@ -0,0 +1,294 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+	duration "github.com/channelmeter/iso8601duration"
+)
+
+// morgenEventInput is the payload of requests that create or change events in the Morgen API.
+type morgenEventInput struct {
+	// ID is only set when changing an existing event.
+	ID         string `json:"id,omitempty"`
+	AccountID  string `json:"accountId"`
+	CalendarID string `json:"calendarId"`
+	Title      string `json:"title,omitempty"`
+	Start      string `json:"start,omitempty"`
+	Duration   string `json:"duration,omitempty"`
+	TimeZone   string `json:"timeZone,omitempty"`
+	// ShowWithoutTime marks all-day events.
+	ShowWithoutTime bool `json:"showWithoutTime,omitempty"`
+	// Participants replace the participants of the event when answering an invitation.
+	Participants map[string]map[string]any `json:"participants,omitempty"`
+}
+
+// morgenSeriesUpdateMode makes changes of recurring events only apply to the given occurrence.
+const morgenSeriesUpdateMode = "single"
+
+// morgenResponses maps the participation statuses of Morgen to the invitation responses shared by
+// all providers. Accepted, declined and tentative are the same in both.
+var morgenResponses = map[string]string{
+	"accepted":     models.ResponseAccepted,
+	"tentative":    models.ResponseTentative,
+	"declined":     models.ResponseDeclined,
+	"needs-action": models.ResponseNeedsAction,
+}
+
+// morgenEventResponseData represents the response structure from Morgen API
+// for a created event. It contains the morgenEvent with its new id.
+type morgenEventResponseData struct {
+	Event morgenEvent `json:"event"`
+}
+
+// morgenEventResponse represents the response structure from Morgen API
+// for a created event. It contains a data field with morgenEventResponseData.
+type morgenEventResponse struct {
+	Data morgenEventResponseData `json:"data"`
+}
+
+// CreateEvent creates the event in the calendar named by its CalendarName, or the default calendar.
+func (m *MorgenProvider) CreateEvent(event models.CalendarEvent) (models.CalendarEvent, error) {
+	calendar, err := m.writableCalendar(event.CalendarName)
+	if err != nil {
+		return models.CalendarEvent{}, err
+	}
+
+	start, timeZone, err := morgenStart(event.StartTime, event.TimeZone)
+	if err != nil {
+		return models.CalendarEvent{}, err
+	}
+	input := morgenEventInput{
+		AccountID:       calendar.AccountId,
+		CalendarID:      calendar.Id,
+		Title:           event.Title,
+		Start:           start,
+		Duration:        morgenDuration(event.EndTime.Sub(event.StartTime)),
+		TimeZone:        timeZone,
+		ShowWithoutTime: event.AllDay,
+	}
+	req, err := m.requests.newJSONRequest(http.MethodPost, "/events/create", nil, input)
+	if err != nil {
+		return models.CalendarEvent{}, err
+	}
+
+	var response morgenEventResponse
+	if err := m.requests.do(req, &response); err != nil {
+		return models.CalendarEvent{}, err
+	}
+
+	event.ID = response.Data.Event.ID
+	event.CalendarName = calendar.Name
+	event.CalendarID = calendar.Id
+	event.AccountID = calendar.AccountId
+	event.Color = calendar.Color
+	return event, nil
+}
+
+// UpdateEvent changes the title and time of the event to the ones of changed. A new time is sent
+// in the timezone of the event, so moving it does not change its timezone.
+func (m *MorgenProvider) UpdateEvent(event, changed models.CalendarEvent) (models.CalendarEvent, error) {
+	calendar, err := m.eventCalendar(event)
+	if err != nil {
+		return models.CalendarEvent{}, err
+	}
+
+	input := morgenEventInput{ID: event.ID, AccountID: calendar.AccountId, CalendarID: calendar.Id}
+	if changed.Title != event.Title {
+		input.Title = changed.Title
+	}
+	if !changed.StartTime.Equal(event.StartTime) || !changed.EndTime.Equal(event.EndTime) {
+		input.Start, input.TimeZone, err = morgenStart(changed.StartTime, event.TimeZone)
+		if err != nil {
+			return models.CalendarEvent{}, err
+		}
+		input.Duration = morgenDuration(changed.EndTime.Sub(changed.StartTime))
+		input.ShowWithoutTime = changed.AllDay
+	}
+	if input.Title == "" && input.Start == "" {
+		return changed, nil
+	}
+
+	if err := m.postEvent("/events/update", input); err != nil {
+		return models.CalendarEvent{}, err
+	}
+	return changed, nil
+}
+
+// DeleteEvent deletes the event.
+func (m *MorgenProvider) DeleteEvent(event models.CalendarEvent) error {
+	calendar, err := m.eventCalendar(event)
+	if err != nil {
+		return err
+	}
+	return m.postEvent("/events/delete", morgenEventInput{ID: event.ID, AccountID: calendar.AccountId, CalendarID: calendar.Id})
+}
+
+// RespondToEvent answers the invitation to the event. The participant of the owner of the account,
+// as retrieved by GetEvents, is updated and the calendar of the account notifies the organizer.
+func (m *MorgenProvider) RespondToEvent(event models.CalendarEvent, response, comment string) error {
+	calendars, err := m.getCalendars()
+	if err != nil {
+		return err
+	}
+	var calendar morgenCalendar
+	for _, candidate := range calendars {
+		if candidate.Id == event.CalendarID {
+			calendar = candidate
+		}
+	}
+	if calendar.Id == "" {
+		return fmt.Errorf("calendar %s not found", event.CalendarID)
+	}
+	if !calendar.CalenderRights.CanRSVP {
+		return fmt.Errorf("invitations in calendar %s cannot be answered", calendar.Name)
+	}
+
+	participants, found := m.participants[morgenOccurrence{event.ID, event.StartTime.Unix()}]
+	if !found {
+		return fmt.Errorf("event %s not found, it was not retrieved from Morgen", event.Title)
+	}
+	owner := ownerParticipant(participants)
+	if owner == nil {
+		return fmt.Errorf("you are not invited to %s", event.Title)
+	}
+	if isOrganizer(owner) {
+		return fmt.Errorf("you organize %s", event.Title)
+	}
+	owner["participationStatus"] = response
+	if comment != "" {
+		owner["participationComment"] = comment
+	}
+
+	return m.postEvent("/events/update", morgenEventInput{
+		ID:           event.ID,
+		AccountID:    calendar.AccountId,
+		CalendarID:   calendar.Id,
+		Participants: participants,
+	})
+}
+
+// ownerParticipant returns the participant of the owner of the account, or nil if the owner is
+// not a participant.
+func ownerParticipant(participants map[string]map[string]any) map[string]any {
+	for _, participant := range participants {
+		if owner, _ := participant["accountOwner"].(bool); owner {
+			return participant
+		}
+	}
+	return nil
+}
+
+// isOrganizer reports whether the participant has the owner role, i.e. organizes the event.
+func isOrganizer(participant map[string]any) bool {
+	roles, _ := participant["roles"].(map[string]any)
+	owner, _ := roles["owner"].(bool)
+	return owner
+}
+
+// participationStatus returns the participation status of the owner of the account, or an empty
+// string if the owner is not a participant or organizes the event.
+func participationStatus(participants map[string]map[string]any) string {
+	owner := ownerParticipant(participants)
+	if isOrganizer(owner) {
+		return ""
+	}
+	status, _ := owner["participationStatus"].(string)
+	return status
+}
+
+// postEvent sends a request changing an existing event, only the given occurrence of recurring events is changed.
+func (m *MorgenProvider) postEvent(path string, input morgenEventInput) error {
+	query := url.Values{}
+	query.Set("seriesUpdateMode", morgenSeriesUpdateMode)
+	req, err := m.requests.newJSONRequest(http.MethodPost, path, query, input)
+	if err != nil {
+		return err
+	}
+	return m.requests.do(req, nil)
+}
+
+// eventCalendar returns the calendar of an event returned by GetEvents and checks that it can be changed.
+func (m *MorgenProvider) eventCalendar(event models.CalendarEvent) (morgenCalendar, error) {
+	if event.ID == "" || event.CalendarID == "" {
+		return morgenCalendar{}, errors.New("event has no id or calendar")
+	}
+
+	calendars, err := m.getCalendars()
+	if err != nil {
+		return morgenCalendar{}, err
+	}
+	for _, calendar := range calendars {
+		if calendar.Id != event.CalendarID {
+			continue
+		}
+		if !calendar.CalenderRights.CanWrite {
+			return morgenCalendar{}, fmt.Errorf("calendar %s is read-only", calendar.Name)
+		}
+		return calendar, nil
+	}
+	return morgenCalendar{}, fmt.Errorf("calendar %s not found", event.CalendarID)
+}
+
+// writableCalendar returns the calendar with the given name, the configured default calendar if
+// the name is empty, or the only calendar the user can write to.
+func (m *MorgenProvider) writableCalendar(name string) (morgenCalendar, error) {
+	if name == "" {
+		name = m.config.DefaultCalendar
+	}
+
+	calendars, err := m.getCalendars()
+	if err != nil {
+		return morgenCalendar{}, err
+	}
+
+	var writable []morgenCalendar
+	var names []string
+	for _, calendar := range calendars {
+		if !calendar.CalenderRights.CanWrite {
+			continue
+		}
+		if name != "" && strings.EqualFold(calendar.Name, name) {
+			return calendar, nil
+		}
+		writable = append(writable, calendar)
+		names = append(names, calendar.Name)
+	}
+
+	switch {
+	case len(writable) == 0:
+		return morgenCalendar{}, errors.New("no calendar found that events can be created in")
+	case name != "":
+		return morgenCalendar{}, fmt.Errorf("calendar %s not found or read-only, available calendars: %s", name, strings.Join(names, ", "))
+	case len(writable) > 1:
+		return morgenCalendar{}, fmt.Errorf("choose a calendar or set default_calendar, available calendars: %s", strings.Join(names, ", "))
+	}
+	return writable[0], nil
+}
+
+// morgenStart formats a time as local date-time and timezone of the Morgen API. The time is sent in
+// the given timezone if it is set. Otherwise times in the system timezone are sent with the IANA name
+// of the system timezone, an error is returned if it cannot be found.
+func morgenStart(t time.Time, timeZone string) (string, string, error) {
+	if timeZone != "" {
+		if loc, err := time.LoadLocation(timeZone); err == nil {
+			return t.In(loc).Format(morgenLocalTimeLayout), timeZone, nil
+		}
+	}
+	name, err := locationName(t.Location())
+	if err != nil {
+		return "", "", err
+	}
+	return t.Format(morgenLocalTimeLayout), name, nil
+}
+
+// morgenDuration formats a duration as ISO 8601 duration rounded to minutes, e.g. PT1H30M.
+func morgenDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	return (&duration.Duration{Hours: minutes / 60, Minutes: minutes % 60}).String()
+}
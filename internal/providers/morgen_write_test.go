@@ -0,0 +1,246 @@
+package providers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+func TestMorgenCreateEvent(t *testing.T) {
+	server := newMorgenStub(t)
+	defer server.Close()
+	provider := newTestMorgenProvider(t, server)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone not available: %v", err)
+	}
+	start := time.Date(2025, 3, 10, 14, 0, 0, 0, berlin)
+	created, err := provider.CreateEvent(models.CalendarEvent{Title: "Review", StartTime: start, EndTime: start.Add(90 * time.Minute)})
+	if err != nil {
+		t.Fatalf("CreateEvent() error = %v", err)
+	}
+	if created.ID != "new-event" || created.CalendarName != "Work" {
+		t.Errorf("created = %+v", created)
+	}
+
+	// Read-only calendars are refused before sending anything
+	if _, err := provider.CreateEvent(models.CalendarEvent{Title: "Review", StartTime: start, EndTime: start, CalendarName: "Shared"}); err == nil {
+		t.Error("CreateEvent() in a read-only calendar should fail")
+	}
+}
+
+func TestMorgenCreateEventDryRun(t *testing.T) {
+	server := newMorgenStub(t)
+	defer server.Close()
+	provider := newTestMorgenProvider(t, server)
+	provider.requests.options.DryRun = true
+	var out strings.Builder
+	provider.requests.out = &out
+
+	start := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	_, err := provider.CreateEvent(models.CalendarEvent{Title: "Review", StartTime: start, EndTime: start.Add(90 * time.Minute), CalendarName: "work"})
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("CreateEvent() error = %v, want ErrDryRun", err)
+	}
+	for _, want := range []string{"POST " + server.URL + "/events/create", `"start": "2025-03-10T14:00:00"`, `"duration": "PT1H30M"`, `"timeZone": "UTC"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dry run output misses %s:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestMorgenUpdateAndDeleteEvent(t *testing.T) {
+	server := newMorgenStub(t)
+	defer server.Close()
+	provider := newTestMorgenProvider(t, server)
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil || len(events) == 0 {
+		t.Fatalf("GetEvents() = %v, %v", events, err)
+	}
+	standup := events[0]
+	moved := standup
+	moved.StartTime = standup.StartTime.Add(time.Hour)
+	moved.EndTime = standup.EndTime.Add(time.Hour)
+
+	updated, err := provider.UpdateEvent(standup, moved)
+	if err != nil {
+		t.Fatalf("UpdateEvent() error = %v", err)
+	}
+	if !updated.StartTime.Equal(moved.StartTime) {
+		t.Errorf("updated start = %v, want %v", updated.StartTime, moved.StartTime)
+	}
+	if err := provider.DeleteEvent(standup); err != nil {
+		t.Errorf("DeleteEvent() error = %v", err)
+	}
+
+	// Events of read-only calendars are refused before sending anything
+	standup.CalendarID = "shared"
+	if err := provider.DeleteEvent(standup); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("DeleteEvent() in a read-only calendar error = %v", err)
+	}
+}
+
+func TestMorgenUpdateEventDryRun(t *testing.T) {
+	server := newMorgenStub(t)
+	defer server.Close()
+	provider := newTestMorgenProvider(t, server)
+	provider.requests.options.DryRun = true
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone not available: %v", err)
+	}
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, berlin)
+	standup := models.CalendarEvent{ID: "e1", CalendarID: "cal", Title: "Standup", StartTime: start, EndTime: start.Add(15 * time.Minute), TimeZone: "Europe/Berlin"}
+
+	// A moved event keeps its timezone, whatever timezone the new time is given in
+	var out strings.Builder
+	provider.requests.out = &out
+	moved := standup
+	moved.StartTime = time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	moved.EndTime = moved.StartTime.Add(15 * time.Minute)
+	if _, err := provider.UpdateEvent(standup, moved); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("UpdateEvent() error = %v, want ErrDryRun", err)
+	}
+	for _, want := range []string{`"start": "2025-03-10T15:00:00"`, `"timeZone": "Europe/Berlin"`, `"duration": "PT15M"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("move output misses %s:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), `"title"`) {
+		t.Errorf("move output changes the title:\n%s", out.String())
+	}
+
+	// A renamed event only gets its new title
+	out.Reset()
+	renamed := standup
+	renamed.Title = "Daily"
+	if _, err := provider.UpdateEvent(standup, renamed); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("UpdateEvent() error = %v, want ErrDryRun", err)
+	}
+	if !strings.Contains(out.String(), `"title": "Daily"`) || strings.Contains(out.String(), `"start"`) || strings.Contains(out.String(), `"timeZone"`) {
+		t.Errorf("rename output should only change the title:\n%s", out.String())
+	}
+}
+
+func TestMorgenParticipationStatus(t *testing.T) {
+	tests := map[string]struct {
+		participants map[string]map[string]any
+		want         string
+	}{
+		"invited": {map[string]map[string]any{
+			"me":   {"accountOwner": true, "roles": map[string]any{"attendee": true}, "participationStatus": "tentative"},
+			"jane": {"roles": map[string]any{"owner": true}, "participationStatus": "accepted"},
+		}, "tentative"},
+		// Events the user organizes have no invitation to answer, so rsvp --all skips them
+		"organizer": {map[string]map[string]any{
+			"me": {"accountOwner": true, "roles": map[string]any{"owner": true, "attendee": true}, "participationStatus": "accepted"},
+		}, ""},
+		"not invited": {map[string]map[string]any{
+			"jane": {"roles": map[string]any{"owner": true}, "participationStatus": "accepted"},
+		}, ""},
+		"no participants": {nil, ""},
+	}
+	for name, test := range tests {
+		if got := participationStatus(test.participants); got != test.want {
+			t.Errorf("%s: participationStatus() = %q, want %q", name, got, test.want)
+		}
+	}
+}
+
+func TestMorgenRespondToEvent(t *testing.T) {
+	server := newMorgenStub(t)
+	defer server.Close()
+	provider := newTestMorgenProvider(t, server)
+	provider.requests.options.DryRun = true
+	var out strings.Builder
+	provider.requests.out = &out
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil || len(events) != 2 {
+		t.Fatalf("GetEvents() = %v, %v", events, err)
+	}
+	if events[0].ResponseStatus != models.ResponseNeedsAction || events[1].ResponseStatus != "" {
+		t.Errorf("response statuses = %q, %q, want needsAction for the invitation only", events[0].ResponseStatus, events[1].ResponseStatus)
+	}
+
+	err = provider.RespondToEvent(events[0], models.ResponseDeclined, "On vacation")
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("RespondToEvent() error = %v, want ErrDryRun", err)
+	}
+	for _, want := range []string{`"participationStatus": "declined"`, `"participationComment": "On vacation"`, `"email": "jane@example.com"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dry run output misses %s:\n%s", want, out.String())
+		}
+	}
+
+	if err := provider.RespondToEvent(events[1], models.ResponseDeclined, ""); err == nil || !strings.Contains(err.Error(), "you ") {
+		t.Errorf("RespondToEvent() error = %v, want a failure for events without an invitation", err)
+	}
+
+	// Another occurrence of the invitation was not retrieved
+	next := events[0]
+	next.StartTime, next.EndTime = next.StartTime.AddDate(0, 0, 7), next.EndTime.AddDate(0, 0, 7)
+	if err := provider.RespondToEvent(next, models.ResponseDeclined, ""); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("RespondToEvent() error = %v, want the event not found", err)
+	}
+}
+
+func TestMorgenStartInSystemTimeZone(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("timezone not available: %v", err)
+	}
+	dir := t.TempDir()
+	link := filepath.Join(dir, "localtime")
+	if err := os.Symlink("/usr/share/zoneinfo/America/New_York", link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	defer func(path string) { localtimePath = path }(localtimePath)
+
+	tests := []struct {
+		name      string
+		tz        string
+		unsetTZ   bool
+		localtime string
+		want      string
+	}{
+		{name: "TZ", tz: "Europe/Berlin", localtime: link, want: "Europe/Berlin"},
+		{name: "TZ path", tz: ":/usr/share/zoneinfo/Asia/Tokyo", localtime: link, want: "Asia/Tokyo"},
+		{name: "empty TZ", tz: "", localtime: link, want: "UTC"},
+		{name: "localtime link", unsetTZ: true, localtime: link, want: "America/New_York"},
+		{name: "unknown", unsetTZ: true, localtime: filepath.Join(dir, "missing")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TZ", tt.tz)
+			if tt.unsetTZ {
+				os.Unsetenv("TZ")
+			}
+			localtimePath = tt.localtime
+
+			start := time.Date(2025, 3, 10, 14, 0, 0, 0, time.Local)
+			got, timeZone, err := morgenStart(start, "")
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("morgenStart() = %s %s, want an error instead of a guessed timezone", got, timeZone)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("morgenStart() error = %v", err)
+			}
+			if got != "2025-03-10T14:00:00" || timeZone != tt.want {
+				t.Errorf("morgenStart() = %s %s, want 2025-03-10T14:00:00 %s", got, timeZone, tt.want)
+			}
+		})
+	}
+}
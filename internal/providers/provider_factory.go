@@ -8,23 +8,31 @@ import (
 
 // ProviderFactory creates calendar providers
 type ProviderFactory struct {
-	config Config
+	config  Config
+	options Options
 }
 
-func NewProviderFactory(config Config) *ProviderFactory {
-	return &ProviderFactory{config: config}
+func NewProviderFactory(config Config, options Options) *ProviderFactory {
+	return &ProviderFactory{config: config, options: options}
 }
 
+// CreateProvider creates the provider configured under the given name with the constructor
+// registered for its type.
 func (f *ProviderFactory) CreateProvider(name string) (CalendarProvider, error) {
 	providerConfig, exists := f.config.Providers[name]
 	if !exists {
 		return nil, fmt.Errorf("provider %s not found in configuration", name)
 	}
 
-	switch name {
-	case "morgen":
-		return NewMorgenProvider(providerConfig), nil
-	default:
-		return nil, fmt.Errorf("unsupported provider: %s", name)
+	providerType := providerConfig.Type
+	if providerType == "" {
+		providerType = name
 	}
+	constructor, err := lookup(providerType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provider %s: %w", name, err)
+	}
+
+	providerConfig.Name = name
+	return constructor(providerConfig, f.options), nil
 }
@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+)
+
+// Constructor creates a provider from its configuration and the options given on the command line.
+type Constructor func(config configs.ProviderConfig, options Options) CalendarProvider
+
+// Options are runtime settings of the providers that come from the command line rather than
+// from the configuration file.
+type Options struct {
+	// Verbose logs every request with secrets redacted.
+	Verbose bool
+	// DryRun prints the requests that change data instead of sending them.
+	DryRun bool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Constructor)
+)
+
+// Register makes a provider type available to the configuration. The built-in providers register
+// themselves, further types can be added before the providers are created. It panics if the type
+// is registered twice.
+func Register(providerType string, constructor Constructor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if constructor == nil {
+		panic("providers: Register constructor is nil for " + providerType)
+	}
+	if _, exists := registry[providerType]; exists {
+		panic("providers: Register called twice for " + providerType)
+	}
+	registry[providerType] = constructor
+}
+
+// Types returns the registered provider types in alphabetical order.
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for providerType := range registry {
+		types = append(types, providerType)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// lookup returns the constructor of a provider type.
+func lookup(providerType string) (Constructor, error) {
+	registryMu.RLock()
+	constructor, exists := registry[providerType]
+	registryMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unsupported provider type %s, available types: %v", providerType, Types())
+	}
+	return constructor, nil
+}
+
+// instanceName returns the name a provider is configured under, or its type if it was
+// created without a name.
+func instanceName(config configs.ProviderConfig, providerType string) string {
+	if config.Name != "" {
+		return config.Name
+	}
+	return providerType
+}
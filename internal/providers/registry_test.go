@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// staticProvider returns a single event titled after its configuration.
+type staticProvider struct {
+	config configs.ProviderConfig
+}
+
+func (p *staticProvider) GetEvents(start, end time.Time) ([]models.CalendarEvent, error) {
+	return []models.CalendarEvent{{Title: p.config.BaseURL, StartTime: start}}, nil
+}
+
+func (p *staticProvider) GetName() string {
+	return instanceName(p.config, "static")
+}
+
+// The test type is registered once, registering it in a test would panic when the tests run again with -count.
+func init() {
+	Register("static-test", func(config configs.ProviderConfig, _ Options) CalendarProvider {
+		return &staticProvider{config: config}
+	})
+}
+
+func TestRegisterAndCreateInstances(t *testing.T) {
+	if !slices.Contains(Types(), "static-test") || !slices.Contains(Types(), "morgen") {
+		t.Fatalf("Types() = %v", Types())
+	}
+
+	factory := NewProviderFactory(configs.Config{Providers: map[string]configs.ProviderConfig{
+		"work":     {Type: "static-test", BaseURL: "work"},
+		"personal": {Type: "static-test", BaseURL: "personal"},
+		"morgen":   {},
+		"team":     {Type: "morgen"},
+		"broken":   {Type: "carrier-pigeon"},
+	}}, Options{})
+
+	for _, name := range []string{"work", "personal"} {
+		provider, err := factory.CreateProvider(name)
+		if err != nil {
+			t.Fatalf("CreateProvider(%s) error = %v", name, err)
+		}
+		events, _ := provider.GetEvents(time.Now(), time.Now())
+		if provider.GetName() != name || events[0].Title != name {
+			t.Errorf("CreateProvider(%s) = %s with %v", name, provider.GetName(), events)
+		}
+	}
+
+	// The type defaults to the name
+	for name, want := range map[string]string{"morgen": "morgen", "team": "team"} {
+		provider, err := factory.CreateProvider(name)
+		if err != nil {
+			t.Fatalf("CreateProvider(%s) error = %v", name, err)
+		}
+		if _, ok := provider.(*MorgenProvider); !ok || provider.GetName() != want {
+			t.Errorf("CreateProvider(%s) = %T %s", name, provider, provider.GetName())
+		}
+	}
+
+	if _, err := factory.CreateProvider("broken"); err == nil || !strings.Contains(err.Error(), "carrier-pigeon") {
+		t.Errorf("CreateProvider(broken) error = %v", err)
+	}
+	if _, err := factory.CreateProvider("missing"); err == nil {
+		t.Error("CreateProvider(missing) should fail")
+	}
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a type twice should panic")
+		}
+	}()
+	Register("morgen", func(config configs.ProviderConfig, _ Options) CalendarProvider { return nil })
+}
@@ -0,0 +1,290 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	"github.com/DeveloperPaul123/agenda/internal/oauth"
+)
+
+// apiKeyPlaceholder is replaced with the API key of the provider in headers and query parameters.
+const apiKeyPlaceholder = "{API_KEY}"
+
+// redacted replaces secrets in verbose logs.
+const redacted = "[REDACTED]"
+
+// ErrDryRun is returned instead of sending a request that changes data when the provider is in dry-run mode.
+var ErrDryRun = errors.New("dry run, the request was not sent")
+
+// maxErrorBodyLength limits how much of an error response body is included in the error.
+const maxErrorBodyLength = 512
+
+// envReferencePattern matches environment variable references like ${VAR}.
+var envReferencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// statusError is returned for responses with a status other than 2xx.
+type statusError struct {
+	StatusCode int
+	// Body is the redacted and possibly truncated response body.
+	Body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// isForbidden reports whether the error is a response with status 403, e.g. because the
+// OAuth token lacks a scope.
+func isForbidden(err error) bool {
+	var statusErr *statusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusForbidden
+}
+
+// requestBuilder creates the HTTP requests of a provider. The configured headers and query
+// parameters are added to every request after expanding {API_KEY} and ${VAR} references.
+type requestBuilder struct {
+	name    string
+	config  configs.ProviderConfig
+	options Options
+	client  *http.Client
+	// apiKey is resolved on first use, so the key command only runs when it is needed.
+	apiKey string
+	// tokens provides the OAuth access token if the provider is configured for OAuth.
+	tokens *oauth.TokenSource
+	// secrets are the values substituted into requests so far, they are redacted from logs and errors.
+	secrets []string
+	// out receives the requests that are not sent in dry-run mode.
+	out io.Writer
+}
+
+// newRequestBuilder creates a request builder for the provider with the given name, configuration and options.
+func newRequestBuilder(name string, config configs.ProviderConfig, options Options) *requestBuilder {
+	builder := &requestBuilder{
+		name:    name,
+		config:  config,
+		options: options,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		out:     os.Stdout,
+	}
+	if config.OAuth != nil {
+		builder.tokens = oauth.NewTokenSource(oauth.NewClient(*config.OAuth), oauth.NewStore(configs.TokenDir()), name)
+	}
+	return builder
+}
+
+// getApiKey retrieves the API key of the provider, resolving it on first use.
+func (b *requestBuilder) getApiKey() (string, error) {
+	if b.apiKey != "" {
+		return b.apiKey, nil
+	}
+
+	apiKey, err := resolveAPIKey(b.name, b.config)
+	if err != nil {
+		return "", err
+	}
+	b.apiKey = apiKey
+	return b.apiKey, nil
+}
+
+// expand replaces {API_KEY} and ${VAR} references in value. The substituted values are
+// remembered as secrets so they can be redacted from logs.
+func (b *requestBuilder) expand(value string) (string, error) {
+	if strings.Contains(value, apiKeyPlaceholder) {
+		apiKey, err := b.getApiKey()
+		if err != nil {
+			return "", err
+		}
+		value = strings.ReplaceAll(value, apiKeyPlaceholder, apiKey)
+		b.addSecret(apiKey)
+	}
+
+	var missing []string
+	value = envReferencePattern.ReplaceAllStringFunc(value, func(reference string) string {
+		name := envReferencePattern.FindStringSubmatch(reference)[1]
+		envValue, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+			return ""
+		}
+		b.addSecret(envValue)
+		return envValue
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s referenced in %s configuration is not set", strings.Join(missing, ", "), b.name)
+	}
+	return value, nil
+}
+
+// newRequest creates a request for the path relative to the base URL of the provider.
+// The given query parameters are merged with the configured ones, which take precedence.
+func (b *requestBuilder) newRequest(method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	return b.newRequestURL(method, strings.TrimSuffix(b.config.BaseURL, "/")+path, query, body)
+}
+
+// newRequestURL creates a request for an absolute URL, which may reference {API_KEY} and ${VAR} as well.
+func (b *requestBuilder) newRequestURL(method, rawURL string, query url.Values, body io.Reader) (*http.Request, error) {
+	expandedURL, err := b.expand(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, expandedURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", b.redactError(err))
+	}
+
+	values := req.URL.Query()
+	for key, value := range query {
+		values[key] = value
+	}
+	for key, value := range b.config.QueryParams {
+		expanded, err := b.expand(value)
+		if err != nil {
+			return nil, err
+		}
+		values.Set(key, expanded)
+	}
+	req.URL.RawQuery = values.Encode()
+
+	for key, value := range b.config.Headers {
+		expanded, err := b.expand(value)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(key, expanded)
+	}
+
+	if b.tokens != nil && req.Header.Get("Authorization") == "" {
+		accessToken, err := b.tokens.AccessToken(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		b.addSecret(accessToken)
+	}
+
+	if b.options.Verbose {
+		b.logRequest(req)
+	}
+	return req, nil
+}
+
+// newJSONRequest creates a request like newRequest with the payload encoded as JSON body.
+func (b *requestBuilder) newJSONRequest(method, path string, query url.Values, payload any) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := b.newRequest(method, path, query, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// do sends the request and decodes the JSON response into out.
+// Responses with a status other than 2xx are returned as errors. In dry-run mode requests
+// that change data are printed instead and ErrDryRun is returned.
+func (b *requestBuilder) do(req *http.Request, out any) error {
+	if b.options.DryRun && req.Method != http.MethodGet {
+		return b.printRequest(req)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", b.redactError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength+1))
+		message := b.redact(string(body))
+		if len(body) > maxErrorBodyLength {
+			message = b.redact(string(body[:maxErrorBodyLength])) + "..."
+		}
+		return &statusError{StatusCode: resp.StatusCode, Body: message}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// printRequest prints the method, URL and JSON payload of a request that is not sent.
+func (b *requestBuilder) printRequest(req *http.Request) error {
+	fmt.Fprintf(b.out, "%s %s\n", req.Method, b.redact(req.URL.String()))
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		payload, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+
+		var indented bytes.Buffer
+		if json.Indent(&indented, payload, "", "  ") == nil {
+			payload = indented.Bytes()
+		}
+		if len(payload) > 0 {
+			fmt.Fprintf(b.out, "%s\n", b.redact(string(payload)))
+		}
+	}
+	return ErrDryRun
+}
+
+// logRequest logs the method, URL and headers of a request with all secrets redacted.
+func (b *requestBuilder) logRequest(req *http.Request) {
+	log.Printf("%s: %s %s", b.name, req.Method, b.redact(req.URL.String()))
+
+	keys := make([]string, 0, len(req.Header))
+	for key := range req.Header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		log.Printf("%s:   %s: %s", b.name, key, b.redact(req.Header.Get(key)))
+	}
+}
+
+// addSecret remembers a value substituted into a request.
+func (b *requestBuilder) addSecret(secret string) {
+	if secret != "" && !slices.Contains(b.secrets, secret) {
+		b.secrets = append(b.secrets, secret)
+	}
+}
+
+// redactError removes secrets from an error message, e.g. a URL error containing a secret feed URL.
+func (b *requestBuilder) redactError(err error) error {
+	if len(b.secrets) == 0 {
+		return err
+	}
+	return errors.New(b.redact(err.Error()))
+}
+
+// redact replaces every occurrence of the secrets in s, including their URL encoded form.
+func (b *requestBuilder) redact(s string) string {
+	for _, secret := range b.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+		s = strings.ReplaceAll(s, url.QueryEscape(secret), redacted)
+	}
+	return s
+}
@@ -0,0 +1,164 @@
+package providers
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	"github.com/DeveloperPaul123/agenda/internal/oauth"
+)
+
+func TestRequestBuilderExpandsHeadersAndQuery(t *testing.T) {
+	t.Setenv("AGENDA_TEST_KEY", "api-secret")
+	t.Setenv("AGENDA_TEST_TENANT", "tenant-1")
+
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	builder := newRequestBuilder("test", configs.ProviderConfig{
+		BaseURL:   server.URL + "/v1/",
+		EnvAPIKey: "AGENDA_TEST_KEY",
+		Headers: map[string]string{
+			"Authorization": "Bearer {API_KEY}",
+			"X-Tenant":      "${AGENDA_TEST_TENANT}",
+		},
+		QueryParams: map[string]string{"key": "{API_KEY}"},
+	}, Options{})
+
+	req, err := builder.newRequest(http.MethodGet, "/events", url.Values{"start": {"today"}}, nil)
+	if err != nil {
+		t.Fatalf("newRequest() error = %v", err)
+	}
+	var response struct{ OK bool }
+	if err := builder.do(req, &response); err != nil {
+		t.Fatalf("do() error = %v", err)
+	}
+
+	if !response.OK {
+		t.Error("response was not decoded")
+	}
+	if got.URL.Path != "/v1/events" {
+		t.Errorf("path = %q, want %q", got.URL.Path, "/v1/events")
+	}
+	if auth := got.Header.Get("Authorization"); auth != "Bearer api-secret" {
+		t.Errorf("Authorization = %q, want %q", auth, "Bearer api-secret")
+	}
+	if tenant := got.Header.Get("X-Tenant"); tenant != "tenant-1" {
+		t.Errorf("X-Tenant = %q, want %q", tenant, "tenant-1")
+	}
+	if key := got.URL.Query().Get("key"); key != "api-secret" {
+		t.Errorf("key = %q, want %q", key, "api-secret")
+	}
+	if start := got.URL.Query().Get("start"); start != "today" {
+		t.Errorf("start = %q, want %q", start, "today")
+	}
+}
+
+func TestRequestBuilderMissingEnv(t *testing.T) {
+	builder := newRequestBuilder("test", configs.ProviderConfig{
+		BaseURL: "http://localhost",
+		Headers: map[string]string{"X-Token": "${AGENDA_TEST_UNSET_VARIABLE}"},
+	}, Options{})
+
+	_, err := builder.newRequest(http.MethodGet, "/", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "AGENDA_TEST_UNSET_VARIABLE") {
+		t.Errorf("newRequest() error = %v, want it to name the variable", err)
+	}
+}
+
+func TestRequestBuilderResolvesKeyOnlyWhenReferenced(t *testing.T) {
+	builder := newRequestBuilder("test", configs.ProviderConfig{
+		BaseURL:       "http://localhost",
+		APIKeyCommand: "exit 1",
+		Headers:       map[string]string{"Accept": "application/json"},
+	}, Options{})
+
+	if _, err := builder.newRequest(http.MethodGet, "/", nil, nil); err != nil {
+		t.Errorf("newRequest() error = %v, the key should not be resolved", err)
+	}
+}
+
+func TestRequestBuilderRedactsVerboseLogs(t *testing.T) {
+	t.Setenv("AGENDA_TEST_KEY", "api/secret")
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	builder := newRequestBuilder("test", configs.ProviderConfig{
+		BaseURL:     "http://localhost",
+		EnvAPIKey:   "AGENDA_TEST_KEY",
+		Headers:     map[string]string{"Authorization": "ApiKey {API_KEY}"},
+		QueryParams: map[string]string{"key": "{API_KEY}"},
+	}, Options{Verbose: true})
+	if _, err := builder.newRequest(http.MethodGet, "/events", nil, nil); err != nil {
+		t.Fatalf("newRequest() error = %v", err)
+	}
+
+	output := logs.String()
+	if strings.Contains(output, "api/secret") || strings.Contains(output, url.QueryEscape("api/secret")) {
+		t.Errorf("verbose log contains the secret:\n%s", output)
+	}
+	if !strings.Contains(output, "ApiKey "+redacted) || !strings.Contains(output, "key="+redacted) {
+		t.Errorf("verbose log does not show the redacted request:\n%s", output)
+	}
+}
+
+func TestRequestBuilderRedactsErrorBodies(t *testing.T) {
+	t.Setenv("AGENDA_TEST_KEY", "api-secret")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("invalid request " + r.URL.String() + strings.Repeat(" padding", 100)))
+	}))
+	defer server.Close()
+
+	builder := newRequestBuilder("test", configs.ProviderConfig{
+		BaseURL:     server.URL,
+		EnvAPIKey:   "AGENDA_TEST_KEY",
+		QueryParams: map[string]string{"key": "{API_KEY}"},
+	}, Options{})
+
+	req, err := builder.newRequest(http.MethodGet, "/events", nil, nil)
+	if err != nil {
+		t.Fatalf("newRequest() error = %v", err)
+	}
+	err = builder.do(req, nil)
+	if err == nil {
+		t.Fatal("do() succeeded, want an error")
+	}
+	if strings.Contains(err.Error(), "api-secret") || !strings.Contains(err.Error(), redacted) {
+		t.Errorf("error contains the secret: %v", err)
+	}
+	if !strings.HasSuffix(err.Error(), "...") || len(err.Error()) > maxErrorBodyLength+100 {
+		t.Errorf("error body was not truncated: %d characters", len(err.Error()))
+	}
+}
+
+func TestRequestBuilderUsesOAuthToken(t *testing.T) {
+	store := oauth.NewStore(t.TempDir())
+	if err := store.Save("test", &oauth.Token{AccessToken: "access-token"}); err != nil {
+		t.Fatal(err)
+	}
+
+	builder := newRequestBuilder("test", configs.ProviderConfig{BaseURL: "http://localhost"}, Options{})
+	builder.tokens = oauth.NewTokenSource(oauth.NewClient(configs.OAuthConfig{}), store, "test")
+
+	req, err := builder.newRequest(http.MethodGet, "/events", nil, nil)
+	if err != nil {
+		t.Fatalf("newRequest() error = %v", err)
+	}
+	if auth := req.Header.Get("Authorization"); auth != "Bearer access-token" {
+		t.Errorf("Authorization = %q, want %q", auth, "Bearer access-token")
+	}
+}
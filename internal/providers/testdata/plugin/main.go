@@ -0,0 +1,82 @@
+// Command plugin is the reference implementation of an exec provider, see ExecProtocolVersion.
+// It adds a focus block from 9:00 to 11:00 on every day of the requested range.
+//
+// The option "mode" makes it misbehave for tests: fail, error, hang or version.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+type request struct {
+	Version  int               `json:"version"`
+	Method   string            `json:"method"`
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end"`
+	Timezone string            `json:"timezone"`
+	Options  map[string]string `json:"options"`
+}
+
+type event struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+	CalendarName string    `json:"calendar_name,omitempty"`
+}
+
+type response struct {
+	Version int     `json:"version"`
+	Events  []event `json:"events"`
+	Error   string  `json:"error,omitempty"`
+}
+
+func main() {
+	var req request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid request: %v\n", err)
+		os.Exit(1)
+	}
+	// Diagnostics go to stderr, stdout is reserved for the response
+	fmt.Fprintf(os.Stderr, "%s from %s to %s\n", req.Method, req.Start.Format(time.RFC3339), req.End.Format(time.RFC3339))
+
+	resp := response{Version: 1}
+	switch req.Options["mode"] {
+	case "fail":
+		fmt.Fprintln(os.Stderr, "could not reach the server")
+		os.Exit(2)
+	case "error":
+		resp.Error = "not authorized"
+	case "hang":
+		time.Sleep(time.Minute)
+	case "version":
+		resp.Version = 2
+	}
+
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	if req.Version == 1 && req.Method == "events" {
+		start := req.Start.In(loc)
+		for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc); day.Before(req.End); day = day.AddDate(0, 0, 1) {
+			focus := event{
+				ID:           "focus-" + day.Format("2006-01-02"),
+				Title:        "Focus",
+				StartTime:    day.Add(9 * time.Hour),
+				EndTime:      day.Add(11 * time.Hour),
+				CalendarName: req.Options["calendar"],
+			}
+			if focus.EndTime.After(req.Start) && focus.StartTime.Before(req.End) {
+				resp.Events = append(resp.Events, focus)
+			}
+		}
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,52 @@
+package providers
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+// locationName returns the IANA name of the location. The system timezone is resolved with
+// systemTimeZone, as Go names it "Local".
+func locationName(loc *time.Location) (string, error) {
+	if loc == time.Local {
+		return systemTimeZone()
+	}
+	return loc.String(), nil
+}
+
+// localtimePath is the link to the zoneinfo file of the system timezone on unix systems.
+var localtimePath = "/etc/localtime"
+
+// systemTimeZone returns the IANA name of the system timezone, which Go only knows as "Local".
+// The name is taken from TZ or from the zoneinfo file /etc/localtime links to.
+func systemTimeZone() (string, error) {
+	if tz, found := os.LookupEnv("TZ"); found {
+		// An empty TZ is UTC
+		if tz == "" {
+			return "UTC", nil
+		}
+		if name, ok := zoneInfoName(strings.TrimPrefix(tz, ":")); ok {
+			return name, nil
+		}
+	}
+	if target, err := os.Readlink(localtimePath); err == nil {
+		if name, ok := zoneInfoName(target); ok {
+			return name, nil
+		}
+	}
+	return "", errors.New("failed to find the name of the system timezone, set timezone in the configuration or the TZ environment variable")
+}
+
+// zoneInfoName returns the IANA name of a timezone given by name or by the path of its zoneinfo
+// file, e.g. /usr/share/zoneinfo/Europe/Berlin.
+func zoneInfoName(zone string) (string, bool) {
+	if _, name, found := strings.Cut(zone, "zoneinfo/"); found {
+		zone = name
+	}
+	if _, err := time.LoadLocation(zone); err != nil || zone == "Local" {
+		return "", false
+	}
+	return zone, true
+}
@@ -0,0 +1,168 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+	"github.com/DeveloperPaul123/agenda/internal/ical"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+	"github.com/DeveloperPaul123/agenda/internal/paths"
+)
+
+// vdirProviderName is the name of the local vdir provider.
+const vdirProviderName = "vdir"
+
+func init() {
+	Register(vdirProviderName, func(config configs.ProviderConfig, _ Options) CalendarProvider {
+		return NewVdirProvider(config)
+	})
+}
+
+// VdirProvider implements CalendarProvider for vdir collections as synchronized by vdirsyncer
+// and read by khal: directories of calendars, each holding one .ics file per event.
+type VdirProvider struct {
+	config configs.ProviderConfig
+}
+
+// vdirCalendar is a directory holding the .ics files of one calendar.
+type vdirCalendar struct {
+	path  string
+	name  string
+	color string
+}
+
+// NewVdirProvider creates a new instance of VdirProvider with the given configuration.
+func NewVdirProvider(config configs.ProviderConfig) *VdirProvider {
+	return &VdirProvider{config: config}
+}
+
+// GetName returns the name of the provider.
+func (p *VdirProvider) GetName() string {
+	return instanceName(p.config, vdirProviderName)
+}
+
+// GetEvents retrieves the events in the given time range from all calendars of the configured
+// collections, with recurring events expanded.
+func (p *VdirProvider) GetEvents(start, end time.Time) ([]models.CalendarEvent, error) {
+	if len(p.config.Paths) == 0 {
+		return nil, errors.New("no collections configured, add them to paths in the vdir provider configuration")
+	}
+
+	var events []models.CalendarEvent
+	for _, path := range p.config.Paths {
+		calendars, err := findCalendars(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, calendar := range calendars {
+			if contains(p.config.CalendarsToIgnore, calendar.name) {
+				continue
+			}
+
+			parsed, err := calendar.load(start.Location())
+			if err != nil {
+				return nil, err
+			}
+			calendarEvents, err := calendarEvents(parsed, start, end)
+			if err != nil {
+				return nil, fmt.Errorf("failed to expand calendar %s: %w", calendar.name, err)
+			}
+			for i := range calendarEvents {
+				calendarEvents[i].CalendarName = calendar.name
+				calendarEvents[i].CalendarID = calendar.path
+				calendarEvents[i].Color = calendar.color
+			}
+			events = append(events, calendarEvents...)
+		}
+	}
+	return events, nil
+}
+
+// findCalendars returns the calendars of a collection. A directory holding .ics files is
+// a calendar itself, so single calendars can be configured as well.
+func findCalendars(path string) ([]vdirCalendar, error) {
+	path, err := paths.ExpandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection: %w", err)
+	}
+
+	var calendars []vdirCalendar
+	isCalendar := false
+	for _, entry := range entries {
+		switch {
+		case entry.IsDir() && !strings.HasPrefix(entry.Name(), "."):
+			calendars = append(calendars, newVdirCalendar(filepath.Join(path, entry.Name())))
+		case !entry.IsDir() && filepath.Ext(entry.Name()) == ".ics":
+			isCalendar = true
+		}
+	}
+	if isCalendar {
+		calendars = append(calendars, newVdirCalendar(path))
+	}
+	return calendars, nil
+}
+
+// newVdirCalendar reads the metadata of the calendar in path. The name falls back to the
+// name of the directory if there is no displayname file.
+func newVdirCalendar(path string) vdirCalendar {
+	calendar := vdirCalendar{
+		path:  path,
+		name:  readMetadata(path, "displayname"),
+		color: readMetadata(path, "color"),
+	}
+	if calendar.name == "" {
+		calendar.name = filepath.Base(path)
+	}
+	return calendar
+}
+
+// readMetadata returns the content of a vdir metadata file, or an empty string if there is none.
+func readMetadata(path, name string) string {
+	data, err := os.ReadFile(filepath.Join(path, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// load parses all events of the calendar into a single calendar, so modified occurrences
+// stored in another file than their recurring event are still applied. Files that cannot be
+// parsed are skipped with a warning.
+func (c vdirCalendar) load(loc *time.Location) (*ical.Calendar, error) {
+	files, err := filepath.Glob(filepath.Join(c.path, "*.ics"))
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{Name: c.name, Color: c.color}
+	for _, file := range files {
+		parsed, err := parseFile(file, loc)
+		if err != nil {
+			log.Printf("Warning: failed to parse %s: %v", file, err)
+			continue
+		}
+		calendar.Events = append(calendar.Events, parsed.Events...)
+	}
+	return calendar, nil
+}
+
+// parseFile parses a single .ics file.
+func parseFile(path string, loc *time.Location) (*ical.Calendar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ical.Parse(file, loc)
+}
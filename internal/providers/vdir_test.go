@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/configs"
+)
+
+// writeFiles creates the files below dir, creating directories as needed.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestVdirProviderGetEvents(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"work/displayname": "Work\n",
+		"work/color":       "#ff0000\n",
+		"work/standup.ics": "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:standup\nSUMMARY:Standup\n" +
+			"DTSTART:20250303T080000Z\nDTEND:20250303T081500Z\nRRULE:FREQ=DAILY\nEND:VEVENT\nEND:VCALENDAR\n",
+		"work/moved.ics": "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:standup\nRECURRENCE-ID:20250310T080000Z\nSUMMARY:Standup (moved)\n" +
+			"DTSTART:20250310T090000Z\nDTEND:20250310T091500Z\nEND:VEVENT\nEND:VCALENDAR\n",
+		"home/gym.ics": "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:gym\nSUMMARY:Gym\n" +
+			"DTSTART:20250310T170000Z\nDTEND:20250310T180000Z\nEND:VEVENT\nEND:VCALENDAR\n",
+		"holidays/displayname": "Holidays",
+		"holidays/day.ics": "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:day\nSUMMARY:Holiday\n" +
+			"DTSTART;VALUE=DATE:20250310\nEND:VEVENT\nEND:VCALENDAR\n",
+		".git/config": "",
+	})
+
+	provider := NewVdirProvider(configs.ProviderConfig{Paths: []string{dir}, CalendarsToIgnore: []string{"Holidays"}})
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+
+	got := map[string]string{}
+	for _, event := range events {
+		got[event.Title] = event.CalendarName + " " + event.Color + " " + event.StartTime.Format("15:04")
+	}
+	if got["Standup (moved)"] != "Work #ff0000 09:00" || got["Gym"] != "home  17:00" {
+		t.Errorf("events = %v", got)
+	}
+}
+
+func TestVdirProviderSingleCalendar(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"event.ics": "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\nSUMMARY:Review\n" +
+			"DTSTART:20250310T130000Z\nDURATION:PT1H\nEND:VEVENT\nEND:VCALENDAR\n",
+	})
+
+	provider := NewVdirProvider(configs.ProviderConfig{Paths: []string{dir}})
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events, err := provider.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil || len(events) != 1 || events[0].CalendarName != filepath.Base(dir) {
+		t.Errorf("GetEvents() = %+v, %v", events, err)
+	}
+
+	// A broken file is skipped, the other events are still returned
+	writeFiles(t, dir, map[string]string{"broken.ics": "BEGIN:VCALENDAR\nBEGIN:VEVENT\n"})
+	events, err = provider.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil || len(events) != 1 || events[0].Title != "Review" {
+		t.Errorf("GetEvents() with a broken file = %+v, %v", events, err)
+	}
+}
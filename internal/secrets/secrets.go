@@ -0,0 +1,126 @@
+// Package secrets resolves provider secrets such as API keys from a command,
+// a file or an environment variable, so they do not have to live in shell rc files.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/DeveloperPaul123/agenda/internal/paths"
+)
+
+// commandTimeout limits how long a secret command, e.g. a password manager, may run.
+const commandTimeout = 30 * time.Second
+
+// ErrNotConfigured is returned when a Source has no command, file or environment variable set.
+var ErrNotConfigured = errors.New("no secret source configured")
+
+// Source describes where a secret is read from. The first non-empty field wins,
+// in the order Command, File, Env.
+type Source struct {
+	// Command is run with the shell and the first line of its output is the secret, e.g. "pass show morgen".
+	Command string
+	// File holds the secret. It must not be readable by other users.
+	File string
+	// Env is the name of an environment variable holding the secret.
+	Env string
+}
+
+// Resolve reads the secret from the configured source.
+func (s Source) Resolve() (string, error) {
+	switch {
+	case s.Command != "":
+		return fromCommand(s.Command)
+	case s.File != "":
+		return fromFile(s.File)
+	case s.Env != "":
+		return fromEnv(s.Env)
+	default:
+		return "", ErrNotConfigured
+	}
+}
+
+// fromCommand runs the command with the shell and returns the first line of its output.
+func fromCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("secret command %q timed out after %s", command, commandTimeout)
+	}
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("secret command %q failed: %w: %s", command, err, message)
+		}
+		return "", fmt.Errorf("secret command %q failed: %w", command, err)
+	}
+
+	secret := firstLine(string(output))
+	if secret == "" {
+		return "", fmt.Errorf("secret command %q printed nothing", command)
+	}
+	return secret, nil
+}
+
+// fromFile reads the secret from a file after checking that only its owner can access it.
+func fromFile(path string) (string, error) {
+	path, err := paths.ExpandHome(path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("secret file %s is a directory", path)
+	}
+	// Windows does not have unix permission bits, ACLs are left to the user there.
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("secret file %s is accessible by other users (mode %04o), restrict it with: chmod 600 %s",
+			path, info.Mode().Perm(), path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	secret := firstLine(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("secret file %s is empty", path)
+	}
+	return secret, nil
+}
+
+// fromEnv reads the secret from an environment variable.
+func fromEnv(name string) (string, error) {
+	secret := strings.TrimSpace(os.Getenv(name))
+	if secret == "" {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return secret, nil
+}
+
+// firstLine returns the first line of s without surrounding whitespace.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
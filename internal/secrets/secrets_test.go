@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestResolveCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	secret, err := Source{Command: "printf 'from-command\\nsecond line\\n'", Env: "UNUSED"}.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if secret != "from-command" {
+		t.Errorf("Resolve() = %q, want %q", secret, "from-command")
+	}
+}
+
+func TestResolveCommandFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	_, err := Source{Command: "echo locked >&2; exit 1"}.Resolve()
+	if err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("Resolve() error = %v, want it to contain the command output", err)
+	}
+
+	if _, err := (Source{Command: "true"}).Resolve(); err == nil {
+		t.Error("Resolve() with empty output should fail")
+	}
+}
+
+func TestResolveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := Source{File: path, Env: "UNUSED"}.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if secret != "from-file" {
+		t.Errorf("Resolve() = %q, want %q", secret, "from-file")
+	}
+}
+
+func TestResolveFilePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not checked on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Source{File: path}.Resolve()
+	if err == nil || !strings.Contains(err.Error(), "chmod 600") {
+		t.Errorf("Resolve() error = %v, want a permission error", err)
+	}
+}
+
+func TestResolveFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := (Source{File: filepath.Join(dir, "missing")}).Resolve(); err == nil {
+		t.Error("Resolve() with a missing file should fail")
+	}
+
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Source{File: empty}).Resolve(); err == nil {
+		t.Error("Resolve() with an empty file should fail")
+	}
+}
+
+func TestResolveEnv(t *testing.T) {
+	t.Setenv("AGENDA_TEST_SECRET", " from-env ")
+
+	secret, err := Source{Env: "AGENDA_TEST_SECRET"}.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if secret != "from-env" {
+		t.Errorf("Resolve() = %q, want %q", secret, "from-env")
+	}
+
+	t.Setenv("AGENDA_TEST_SECRET", "")
+	_, err = Source{Env: "AGENDA_TEST_SECRET"}.Resolve()
+	if err == nil || !strings.Contains(err.Error(), "AGENDA_TEST_SECRET") {
+		t.Errorf("Resolve() error = %v, want it to name the variable", err)
+	}
+}
+
+func TestResolveNotConfigured(t *testing.T) {
+	if _, err := (Source{}).Resolve(); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Resolve() error = %v, want ErrNotConfigured", err)
+	}
+}
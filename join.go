@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// newJoinCommand creates the command that opens the meeting link of the current or next meeting.
+func newJoinCommand() *cobra.Command {
+	joinCmd := &cobra.Command{
+		Use:   "join",
+		Short: "Open the meeting link of the current or next meeting",
+		Run:   runJoin,
+	}
+	joinCmd.Flags().Bool("print", false, "Only print the meeting link instead of opening it")
+	return joinCmd
+}
+
+// runJoin finds the current or next meeting with a meeting link and opens it in the browser.
+func runJoin(cmd *cobra.Command, args []string) {
+	config := loadConfig(cmd)
+	printOnly, _ := cmd.Flags().GetBool("print")
+
+	now := currentTime(config)
+	start := startOfDay(now)
+	events := fetchEvents(cmd, config, start, start.AddDate(0, 0, 1))
+
+	event, found := nextMeeting(events, now)
+	if !found {
+		log.Fatalf("No current or upcoming meeting with a meeting link found for today.")
+	}
+
+	if printOnly {
+		fmt.Println(event.MeetingURL)
+		return
+	}
+
+	fmt.Printf("Joining %s: %s\n", event.Title, event.MeetingURL)
+	if err := openURL(event.MeetingURL); err != nil {
+		log.Fatalf("Failed to open meeting link: %v", err)
+	}
+}
+
+// nextMeeting returns the first event of the sorted list with a meeting link that has not ended yet.
+// A meeting that is already running is preferred over one that is about to start.
+func nextMeeting(events []models.CalendarEvent, now time.Time) (models.CalendarEvent, bool) {
+	for _, event := range events {
+		if event.MeetingURL != "" && event.EndTime.After(now) {
+			return event, true
+		}
+	}
+	return models.CalendarEvent{}, false
+}
+
+// openURL opens the link with the default application of the operating system.
+func openURL(url string) error {
+	var openCmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		openCmd = exec.Command("open", url)
+	case "windows":
+		openCmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		openCmd = exec.Command("xdg-open", url)
+	}
+	return openCmd.Start()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+func TestNextMeeting(t *testing.T) {
+	withLink := func(event models.CalendarEvent) models.CalendarEvent {
+		event.MeetingURL = "https://meet.google.com/" + event.ID
+		return event
+	}
+	standup := withLink(testEvent("Standup", "09:00", "09:30"))
+	focus := testEvent("Focus", "10:00", "12:00")
+	review := withLink(testEvent("Review", "10:30", "11:30"))
+	retro := withLink(testEvent("Retro", "11:00", "12:00"))
+	events := []models.CalendarEvent{standup, focus, review, retro}
+
+	tests := []struct {
+		name string
+		now  string
+		want string
+	}{
+		{"before the first meeting", "08:00", "standup"},
+		{"during a meeting", "09:15", "standup"},
+		{"when a meeting ends", "09:30", "review"},
+		{"skips events without a link", "10:00", "review"},
+		{"prefers the running meeting", "11:15", "review"},
+		{"after the running meeting", "11:30", "retro"},
+		{"after the last meeting", "12:00", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, found := nextMeeting(events, testTime(tt.now))
+			if found != (tt.want != "") || event.ID != tt.want {
+				t.Errorf("nextMeeting() = %q, %t, want %q", event.ID, found, tt.want)
+			}
+		})
+	}
+}
@@ -6,12 +6,16 @@ import (
 	"os"
 	"sort"
 	"strings"
-	"text/template"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	analysis "github.com/DeveloperPaul123/agenda/internal/analysis"
 	configs "github.com/DeveloperPaul123/agenda/internal/configs"
+	dateparse "github.com/DeveloperPaul123/agenda/internal/dateparse"
+	description "github.com/DeveloperPaul123/agenda/internal/description"
+	locale "github.com/DeveloperPaul123/agenda/internal/locale"
+	meetings "github.com/DeveloperPaul123/agenda/internal/meetings"
 	models "github.com/DeveloperPaul123/agenda/internal/models"
 	providers "github.com/DeveloperPaul123/agenda/internal/providers"
 	spinner "github.com/briandowns/spinner"
@@ -23,43 +27,23 @@ var (
 	commit  = "none"
 )
 
-// EventFormatter handles formatting events for output to the console.
-type EventFormatter struct {
-	timeFormat    string
-	eventTemplate *template.Template
-}
-
-// NewEventFormatter creates a new EventFormatter with the given time format and event template string.
-func NewEventFormatter(timeFormat, eventTemplateStr string) (*EventFormatter, error) {
-	tmpl, err := template.New("event").Parse(eventTemplateStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse event template: %w", err)
+// printConflicts prints a report of all overlapping events.
+func printConflicts(formatter *EventFormatter, events []models.CalendarEvent, conflicts []analysis.Conflict) {
+	if len(conflicts) == 0 {
+		fmt.Println("No conflicts found.")
+		return
 	}
-	return &EventFormatter{
-		timeFormat:    timeFormat,
-		eventTemplate: tmpl,
-	}, nil
-}
 
-// FormatEvent formats a CalendarEvent using the configured template and time format.
-func (f *EventFormatter) FormatEvent(event models.CalendarEvent) (string, error) {
-	data := struct {
-		models.CalendarEvent
-		StartTimeFormatted string
-		EndTimeFormatted   string
-		Duration           string
-	}{
-		CalendarEvent:      event,
-		StartTimeFormatted: event.StartTime.Format(f.timeFormat),
-		EndTimeFormatted:   event.EndTime.Format(f.timeFormat),
-		Duration:           event.EndTime.Sub(event.StartTime).String(),
-	}
-
-	var result strings.Builder
-	if err := f.eventTemplate.Execute(&result, data); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
-	}
-	return result.String(), nil
+	for _, conflict := range conflicts {
+		first, second := events[conflict.First], events[conflict.Second]
+		if conflict.BackToBack() {
+			fmt.Printf("- %s %s is back-to-back with %s %s\n",
+				formatter.FormatTimeRange(first), first.Title, formatter.FormatTimeRange(second), second.Title)
+			continue
+		}
+		fmt.Printf("- %s %s overlaps %s %s by %s\n",
+			formatter.FormatTimeRange(first), first.Title, formatter.FormatTimeRange(second), second.Title, formatDuration(conflict.Overlap))
+	}
 }
 
 // initConfig initializes the default configuration file.
@@ -69,17 +53,17 @@ func initConfig(cmd *cobra.Command, args []string) {
 		log.Fatalf("Failed to create config file: %v", err)
 	}
 	fmt.Printf("Created default configuration file at: %s\n", configs.DefaultConfigPath())
-	fmt.Printf("Please set your API key in the %s environment variable.\n", config.Providers[config.Provider].EnvAPIKey)
+	fmt.Printf("Please set your API key in the %s environment variable, or configure api_key_command or api_key_file.\n", config.Providers[config.Provider].EnvAPIKey)
 }
 
-// runAgenda is the main function that runs the agenda command.
-func runAgenda(cmd *cobra.Command, args []string) {
+// loadConfig reads the configuration file and applies the command line overrides shared by all commands.
+func loadConfig(cmd *cobra.Command) configs.Config {
 	configPath, _ := cmd.Flags().GetString("config")
 	provider, _ := cmd.Flags().GetString("provider")
 	timeFormat, _ := cmd.Flags().GetString("time-format")
-	eventTemplate, _ := cmd.Flags().GetString("event-template")
+	timezone, _ := cmd.Flags().GetString("tz")
+	localeName, _ := cmd.Flags().GetString("locale")
 	verbose, _ := cmd.Flags().GetBool("verbose")
-	dateStr, _ := cmd.Flags().GetString("date")
 
 	if configPath == "" {
 		configPath = configs.DefaultConfigPath()
@@ -96,43 +80,175 @@ func runAgenda(cmd *cobra.Command, args []string) {
 	if timeFormat != "" {
 		config.TimeFormat = timeFormat
 	}
-	if eventTemplate != "" {
-		config.EventTemplate = eventTemplate
+	if timezone != "" {
+		config.Timezone = timezone
 	}
-
-	useDate := time.Now()
-	if dateStr != "" {
-		parsedDate, err := time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			log.Fatalf("Invalid date format: %v. Use YYYY-MM-DD.", err)
-		}
-		useDate = parsedDate
+	if localeName != "" {
+		config.Locale = localeName
 	}
 
 	if verbose {
 		log.Printf("Using provider: %s", config.Provider)
 		log.Printf("Time format: %s", config.TimeFormat)
-		log.Printf("Event template: %s", config.EventTemplate)
+		log.Printf("Timezone: %s", currentTime(config).Location())
 	}
 
-	factory := providers.NewProviderFactory(config)
-	calProvider, err := factory.CreateProvider(config.Provider)
+	return config
+}
+
+// currentTime returns the current time in the configured timezone.
+func currentTime(config configs.Config) time.Time {
+	loc, err := config.Location()
 	if err != nil {
-		log.Fatalf("Failed to create provider: %v", err)
+		log.Fatalf("Invalid timezone: %v", err)
 	}
-	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-	s.Start()
+	return time.Now().In(loc)
+}
 
-	events, err := calProvider.GetTodaysEvents(useDate)
-	s.Stop()
+// parseDateFlag parses the date or range of dates given in the named flag relative to now.
+// Returns the range [start, end) of whole days, today if the flag is not set.
+func parseDateFlag(cmd *cobra.Command, name string, now time.Time) (time.Time, time.Time) {
+	dateStr, _ := cmd.Flags().GetString(name)
+	if dateStr == "" {
+		dateStr = "today"
+	}
+
+	start, end, err := dateparse.ParseRange(dateStr, now)
 	if err != nil {
-		log.Fatalf("Failed to get events: %v", err)
+		log.Fatalf("Invalid date: %v", err)
 	}
+	return start, end
+}
+
+// startOfDay returns midnight of the day of the given time in its location.
+func startOfDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
 
-	if len(events) == 0 {
-		fmt.Println("No events found for today.")
+// newFormatter creates the event formatter for the configuration.
+func newFormatter(config configs.Config) *EventFormatter {
+	formatter, err := NewEventFormatter(config.TimeFormat, config.EventTemplate)
+	if err != nil {
+		log.Fatalf("Failed to create formatter: %v", err)
+	}
+
+	descriptions, err := description.NewNormalizer(config.DescriptionStripPatterns, config.DescriptionMaxLength)
+	if err != nil {
+		log.Fatalf("Failed to create formatter: %v", err)
+	}
+	formatter.SetDescriptionNormalizer(descriptions)
+
+	if err := formatter.SetExtraTimezones(config.ExtraTimezones); err != nil {
+		log.Fatalf("Failed to create formatter: %v", err)
+	}
+
+	dateLocale, err := locale.Get(config.Locale)
+	if err != nil {
+		log.Fatalf("Failed to create formatter: %v", err)
+	}
+	formatter.SetLocale(dateLocale, config.DateFormat)
+
+	return formatter
+}
+
+// providerOptions returns the provider options set with the command line flags.
+func providerOptions(cmd *cobra.Command) providers.Options {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	// Only the commands that change events have the --dry-run flag
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	return providers.Options{Verbose: verbose, DryRun: dryRun}
+}
+
+// createProviders creates the configured provider and the providers merged with it.
+// Returns the names the providers are configured under and the providers.
+func createProviders(cmd *cobra.Command, config configs.Config) ([]string, []providers.CalendarProvider) {
+	factory := providers.NewProviderFactory(config, providerOptions(cmd))
+	names := append([]string{config.Provider}, config.MergeProviders...)
+	calProviders := make([]providers.CalendarProvider, len(names))
+	for i, name := range names {
+		calProvider, err := factory.CreateProvider(name)
+		if err != nil {
+			log.Fatalf("Failed to create provider: %v", err)
+		}
+		calProviders[i] = calProvider
+	}
+	return names, calProviders
+}
+
+// printTasks prints the tasks after a heading, nothing is printed if there are no tasks.
+func printTasks(config configs.Config, formatter *EventFormatter, tasks []models.Task) {
+	if len(tasks) == 0 {
 		return
 	}
+	heading, err := formatter.FormatTaskHeading(config.TaskHeadingTemplate, tasks)
+	if err != nil {
+		log.Fatalf("Failed to format task heading: %v", err)
+	}
+	fmt.Printf("\n%s\n", heading)
+	for _, task := range tasks {
+		formatted, err := formatter.FormatTask(config.TaskTemplate, task)
+		if err != nil {
+			log.Fatalf("Failed to format task: %v", err)
+		}
+		fmt.Println(formatted)
+	}
+}
+
+// fetchEvents retrieves the events in [start, end) from the configured provider and the providers merged with it.
+// Duplicate events are removed and the result is sorted by start time.
+func fetchEvents(cmd *cobra.Command, config configs.Config, start, end time.Time) []models.CalendarEvent {
+	events, _ := fetchAgenda(cmd, config, start, end, false)
+	return events
+}
+
+// fetchAgenda retrieves the events like fetchEvents, and with withTasks also the tasks of [start, end) and
+// the overdue tasks from the providers that manage tasks. The tasks are retrieved with the events the
+// provider returned, so they are not requested again to find the scheduled tasks.
+func fetchAgenda(cmd *cobra.Command, config configs.Config, start, end time.Time, withTasks bool) ([]models.CalendarEvent, []models.Task) {
+	names, calProviders := createProviders(cmd, config)
+	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+	s.Start()
+
+	var events []models.CalendarEvent
+	var tasks []models.Task
+	tasksSupported := false
+	for i, calProvider := range calProviders {
+		providerEvents, err := calProvider.GetEvents(start, end)
+		if err != nil {
+			s.Stop()
+			log.Fatalf("Failed to get events from %s: %v", names[i], err)
+		}
+
+		for j := range providerEvents {
+			// Show the events in the timezone the agenda is requested for
+			providerEvents[j].StartTime = providerEvents[j].StartTime.In(start.Location())
+			providerEvents[j].EndTime = providerEvents[j].EndTime.In(start.Location())
+			providerEvents[j].Provider = names[i]
+			if providerEvents[j].MeetingURL == "" {
+				providerEvents[j].MeetingURL = meetings.ExtractURL(providerEvents[j].Location, providerEvents[j].Description)
+			}
+		}
+		events = append(events, providerEvents...)
+
+		taskProvider, ok := calProvider.(providers.TaskProvider)
+		if !withTasks || !ok {
+			continue
+		}
+		tasksSupported = true
+		providerTasks, err := taskProvider.GetTasks(start, end, providerEvents)
+		if err != nil {
+			s.Stop()
+			log.Fatalf("Failed to get tasks from %s: %v", names[i], err)
+		}
+		for j := range providerTasks {
+			providerTasks[j].Provider = names[i]
+		}
+		tasks = append(tasks, providerTasks...)
+	}
+	s.Stop()
+	if withTasks && !tasksSupported {
+		log.Printf("Warning: none of the providers %s manages tasks", strings.Join(names, ", "))
+	}
 
 	uniqueEvents := make(map[string]models.CalendarEvent)
 	for _, event := range events {
@@ -142,11 +258,6 @@ func runAgenda(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	if len(uniqueEvents) == 0 {
-		fmt.Println("No events today")
-		return
-	}
-
 	// Convert uniqueEvents map back to a list
 	sortedEvents := make([]models.CalendarEvent, 0, len(uniqueEvents))
 	for _, event := range uniqueEvents {
@@ -154,19 +265,107 @@ func runAgenda(cmd *cobra.Command, args []string) {
 	}
 
 	sort.Slice(sortedEvents, func(i, j int) bool {
-		return sortedEvents[i].StartTime.Local().Before(sortedEvents[j].StartTime.Local())
+		return sortedEvents[i].StartTime.Before(sortedEvents[j].StartTime)
 	})
 
-	formatter, err := NewEventFormatter(config.TimeFormat, config.EventTemplate)
+	return sortedEvents, tasks
+}
+
+// summarize computes the meeting statistics of the events in [start, end) using the configured working hours.
+func summarize(config configs.Config, events []models.CalendarEvent, start, end time.Time) analysis.Summary {
+	workStart, workEnd, err := config.WorkingHours()
 	if err != nil {
-		log.Fatalf("Failed to create formatter: %v", err)
+		log.Fatalf("Invalid working hours: %v", err)
+	}
+	return analysis.Summarize(events, start, end, analysis.WorkingHours{Start: workStart, End: workEnd})
+}
+
+// runAgenda is the main function that runs the agenda command.
+func runAgenda(cmd *cobra.Command, args []string) {
+	config := loadConfig(cmd)
+	eventTemplate, _ := cmd.Flags().GetString("event-template")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	conflictsOnly, _ := cmd.Flags().GetBool("conflicts")
+	backToBack, _ := cmd.Flags().GetBool("back-to-back")
+	groupBy, _ := cmd.Flags().GetString("group-by")
+	showIDs, _ := cmd.Flags().GetBool("ids")
+
+	if eventTemplate != "" {
+		config.EventTemplate = eventTemplate
+	}
+	if groupBy != "" {
+		config.GroupBy = groupBy
+	}
+	if cmd.Flags().Changed("pretty") {
+		config.Pretty, _ = cmd.Flags().GetBool("pretty")
+	}
+	if config.GroupHeadingTemplate == "" {
+		config.GroupHeadingTemplate = configs.DEFAULT_GROUP_HEADING_TEMPLATE
+	}
+	if config.DayHeadingTemplate == "" {
+		config.DayHeadingTemplate = configs.DEFAULT_DAY_HEADING_TEMPLATE
+	}
+	if cmd.Flags().Changed("tasks") {
+		config.ShowTasks, _ = cmd.Flags().GetBool("tasks")
 	}
+	if config.TaskTemplate == "" {
+		config.TaskTemplate = configs.DEFAULT_TASK_TEMPLATE
+	}
+	if config.TaskHeadingTemplate == "" {
+		config.TaskHeadingTemplate = configs.DEFAULT_TASK_HEADING_TEMPLATE
+	}
+	if verbose {
+		log.Printf("Event template: %s", config.EventTemplate)
+	}
+
+	now := currentTime(config)
+	start, end := parseDateFlag(cmd, "date", now)
 
-	for _, event := range sortedEvents {
-		formatted, err := formatter.FormatEvent(event)
+	sortedEvents, tasks := fetchAgenda(cmd, config, start, end, config.ShowTasks)
+
+	formatter := newFormatter(config)
+	if len(sortedEvents) == 0 {
+		fmt.Println("No events found.")
+		printTasks(config, formatter, tasks)
+		return
+	}
+
+	conflicts := analysis.FindConflicts(sortedEvents, backToBack)
+	if conflictsOnly {
+		printConflicts(formatter, sortedEvents, conflicts)
+		return
+	}
+
+	// Collect the titles of overlapping events for each event
+	overlapping := make([][]string, len(sortedEvents))
+	for _, conflict := range conflicts {
+		overlapping[conflict.First] = append(overlapping[conflict.First], sortedEvents[conflict.Second].Title)
+		overlapping[conflict.Second] = append(overlapping[conflict.Second], sortedEvents[conflict.First].Title)
+	}
+
+	var renderer agendaRenderer = &markdownRenderer{out: os.Stdout, formatter: formatter, config: config, showIDs: showIDs}
+	if config.Pretty && isTerminal() {
+		renderer = &prettyRenderer{out: os.Stdout, formatter: formatter, now: now, showIDs: showIDs}
+	}
+
+	// Only show day headings if the agenda spans more than one day
+	multipleDays := start.AddDate(0, 0, 1).Before(end)
+	for i, day := range splitByDay(sortedEvents, overlapping, start, end) {
+		groups, err := groupEvents(day.events, config.GroupBy)
 		if err != nil {
-			log.Printf("Warning: failed to format event %s: %v", event.Title, err)
-			continue
+			log.Fatalf("Failed to group events: %v", err)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		renderer.Render(day, groups, multipleDays, config.GroupBy != groupByNone)
+	}
+	printTasks(config, formatter, tasks)
+
+	if config.SummaryTemplate != "" {
+		formatted, err := formatter.FormatSummary(config.SummaryTemplate, summarize(config, sortedEvents, start, end))
+		if err != nil {
+			log.Fatalf("Failed to format summary: %v", err)
 		}
 		fmt.Println(formatted)
 	}
@@ -186,12 +385,20 @@ func main() {
 	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true})
 
 	// Define flags
-	rootCmd.Flags().String("config", "", "Path to configuration file (default: ~/.config/agenda/config.yaml)")
-	rootCmd.Flags().String("provider", "", "Override the provider from config")
-	rootCmd.Flags().String("time-format", "", "Override the time format from config")
+	rootCmd.PersistentFlags().String("config", "", "Path to configuration file (default: ~/.config/agenda/config.yaml)")
+	rootCmd.PersistentFlags().String("provider", "", "Override the provider from config")
+	rootCmd.PersistentFlags().String("time-format", "", "Override the time format from config, a Go layout or 24h, 12h or iso")
+	rootCmd.PersistentFlags().String("locale", "", "Override the locale from config used for day and month names: en, de, fr or es")
+	rootCmd.PersistentFlags().String("tz", "", "Timezone to render the agenda in, e.g. America/Los_Angeles (default is the system timezone)")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().String("date", "", "Date or week to get events for, e.g. 2025-03-10, tomorrow, next fri, +2d or 2025-W11 (default is today)")
 	rootCmd.Flags().String("event-template", "", "Override the event template from config")
-	rootCmd.Flags().Bool("verbose", false, "Enable verbose logging")
-	rootCmd.Flags().String("date", "", "Date to get events for (format: YYYY-MM-DD, default is today)")
+	rootCmd.Flags().Bool("conflicts", false, "Only print a report of overlapping events")
+	rootCmd.Flags().Bool("back-to-back", false, "Also treat meetings without any gap between them as conflicts")
+	rootCmd.Flags().Bool("pretty", false, "Use colored terminal output when stdout is a terminal")
+	rootCmd.Flags().String("group-by", "", "Group events by calendar, account, provider or morning-afternoon")
+	rootCmd.Flags().Bool("tasks", false, "Show the tasks due or scheduled on the date and overdue tasks")
+	rootCmd.Flags().Bool("ids", false, "Show the ID of each event, e.g. to target it with agenda move")
 
 	var initCmd = &cobra.Command{
 		Use:   "init",
@@ -199,6 +406,14 @@ func main() {
 		Run:   initConfig,
 	}
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(newStatsCommand())
+	rootCmd.AddCommand(newJoinCommand())
+	rootCmd.AddCommand(newAuthCommand())
+	rootCmd.AddCommand(newAddCommand())
+	rootCmd.AddCommand(newMoveCommand())
+	rootCmd.AddCommand(newRenameCommand())
+	rootCmd.AddCommand(newDeleteCommand())
+	rootCmd.AddCommand(newRSVPCommand())
 
 	// Execute the root command
 	if err := rootCmd.Execute(); err != nil {
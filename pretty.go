@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// Styles used by the pretty renderer.
+var (
+	timeStyle      = color.New(color.FgCyan)
+	secondaryStyle = color.New(color.FgBlue)
+	pastStyle      = color.New(color.Faint)
+	currentStyle   = color.New(color.Bold, color.FgGreen)
+	dayStyle       = color.New(color.Bold, color.FgMagenta)
+	headingStyle   = color.New(color.Bold, color.Underline)
+	conflictStyle  = color.New(color.FgYellow)
+	nowStyle       = color.New(color.FgRed)
+)
+
+// isTerminal reports whether stdout is an interactive terminal that supports colors.
+// The color package already determines this when it is initialized.
+func isTerminal() bool {
+	return !color.NoColor
+}
+
+// prettyRenderer renders the agenda as colored, human friendly terminal output.
+type prettyRenderer struct {
+	out       io.Writer
+	formatter *EventFormatter
+	now       time.Time
+	// showIDs adds the ID of each event as last column.
+	showIDs bool
+}
+
+// Render prints the grouped events of the day. Past events are dimmed, the current event is highlighted
+// and a divider line marks the current time if the day is today.
+func (r *prettyRenderer) Render(day agendaDay, groups []eventGroup, showDayHeading, showGroupHeadings bool) {
+	timeWidth := 0
+	for _, event := range day.events {
+		timeWidth = max(timeWidth, len(r.formatter.FormatTimeRange(event)))
+	}
+
+	if showDayHeading {
+		fmt.Fprintln(r.out, dayStyle.Sprint(r.formatter.FormatDate(day.Date)))
+	}
+
+	// The divider is printed once per day, before the first event that has not ended yet
+	dividerPrinted := r.now.Before(day.Date) || !r.now.Before(day.Date.AddDate(0, 0, 1))
+	for i, group := range groups {
+		if showGroupHeadings {
+			if i > 0 {
+				fmt.Fprintln(r.out)
+			}
+			fmt.Fprintln(r.out, headingStyle.Sprint(group.Name))
+		}
+
+		for _, index := range group.indices {
+			event := day.events[index]
+			if !dividerPrinted && event.EndTime.After(r.now) {
+				r.printDivider(timeWidth)
+				dividerPrinted = true
+			}
+			r.printEvent(event, day.overlapping[index], timeWidth)
+		}
+	}
+	if !dividerPrinted {
+		r.printDivider(timeWidth)
+	}
+}
+
+// isCurrent reports whether the event is happening right now.
+func (r *prettyRenderer) isCurrent(event models.CalendarEvent) bool {
+	return !r.now.Before(event.StartTime) && r.now.Before(event.EndTime)
+}
+
+// printEvent prints a single event line. The times in the extra timezones are shown in a secondary column.
+func (r *prettyRenderer) printEvent(event models.CalendarEvent, conflicts []string, timeWidth int) {
+	timeColumn := fmt.Sprintf("%-*s", timeWidth, r.formatter.FormatTimeRange(event))
+	secondaryColumn := strings.Join(r.formatter.ExtraTimes(event), "  ")
+
+	calendar := ""
+	if event.CalendarName != "" {
+		calendar = fmt.Sprintf("(%s)", event.CalendarName)
+	}
+	conflict := ""
+	if len(conflicts) > 0 {
+		conflict = fmt.Sprintf("⚠ overlaps %s", strings.Join(conflicts, ", "))
+	}
+	id := ""
+	if r.showIDs && event.ID != "" {
+		id = fmt.Sprintf("[%s]", event.ID)
+	}
+
+	var line string
+	switch {
+	case r.isCurrent(event):
+		line = joinColumns(currentStyle.Sprint("▶")+" "+currentStyle.Sprint(timeColumn), styled(secondaryStyle, secondaryColumn),
+			calendarBullet(event.Color), currentStyle.Sprint(event.Title), calendar, styled(conflictStyle, conflict), styled(pastStyle, id))
+	case !event.EndTime.After(r.now):
+		line = pastStyle.Sprint(joinColumns("  "+timeColumn, secondaryColumn, "●", event.Title, calendar, conflict, id))
+	default:
+		line = joinColumns("  "+timeStyle.Sprint(timeColumn), styled(secondaryStyle, secondaryColumn),
+			calendarBullet(event.Color), event.Title, calendar, styled(conflictStyle, conflict), styled(pastStyle, id))
+	}
+	fmt.Fprintln(r.out, line)
+}
+
+// styled applies the style to non-empty text.
+func styled(style *color.Color, text string) string {
+	if text == "" {
+		return ""
+	}
+	return style.Sprint(text)
+}
+
+// joinColumns joins the non-empty columns with a space.
+func joinColumns(columns ...string) string {
+	nonEmpty := columns[:0]
+	for _, column := range columns {
+		if column != "" {
+			nonEmpty = append(nonEmpty, column)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
+
+// printDivider prints the line marking the current time.
+func (r *prettyRenderer) printDivider(timeWidth int) {
+	label := fmt.Sprintf(" now %s ", r.formatter.formatTime(r.now))
+	fmt.Fprintln(r.out, nowStyle.Sprint(strings.Repeat("─", 2)+label+strings.Repeat("─", max(timeWidth, 20))))
+}
+
+// calendarBullet returns a bullet in the given calendar color. Colors that can not be parsed
+// result in a plain bullet.
+func calendarBullet(hexColor string) string {
+	r, g, b, ok := parseHexColor(hexColor)
+	if !ok || color.NoColor {
+		return "●"
+	}
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm●\x1b[0m", r, g, b)
+}
+
+// parseHexColor parses colors in the #rrggbb or #rgb format.
+func parseHexColor(hexColor string) (uint8, uint8, uint8, bool) {
+	hexColor = strings.TrimPrefix(hexColor, "#")
+	if len(hexColor) == 3 {
+		hexColor = string([]byte{hexColor[0], hexColor[0], hexColor[1], hexColor[1], hexColor[2], hexColor[2]})
+	}
+	if len(hexColor) != 6 {
+		return 0, 0, 0, false
+	}
+	value, err := strconv.ParseUint(hexColor, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(value >> 16), uint8(value >> 8), uint8(value), true
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// renderPretty renders the days with the pretty renderer without colors and returns the output
+// lines without trailing spaces.
+func renderPretty(t *testing.T, now time.Time, days []agendaDay, groupBy string) string {
+	t.Helper()
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	formatter, err := NewEventFormatter("15:04", "{{.Title}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	renderer := &prettyRenderer{out: &out, formatter: formatter, now: now}
+	for _, day := range days {
+		groups, err := groupEvents(day.events, groupBy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		renderer.Render(day, groups, len(days) > 1, groupBy != groupByNone)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// divider is the line marking the current time for events with a time column of at most 20 characters.
+func divider(clock string) string {
+	return "── now " + clock + " " + strings.Repeat("─", 20)
+}
+
+func TestPrettyRendererDivider(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events := []models.CalendarEvent{
+		testEvent("Standup", "09:00", "09:15"),
+		testEvent("Review", "09:45", "10:30"),
+		testEvent("Lunch", "12:00", "13:00"),
+		testEvent("Retro", "15:00", "16:00"),
+	}
+	agenda := splitByDay(events, make([][]string, len(events)), day, day.AddDate(0, 0, 1))
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		groupBy string
+		want    []string
+	}{
+		{
+			name: "before the next event",
+			now:  testTime("11:00"),
+			want: []string{"  09:00-09:15 ● Standup", "  09:45-10:30 ● Review", divider("11:00"), "  12:00-13:00 ● Lunch", "  15:00-16:00 ● Retro"},
+		},
+		{
+			name: "during an event",
+			now:  testTime("10:00"),
+			want: []string{"  09:00-09:15 ● Standup", divider("10:00"), "▶ 09:45-10:30 ● Review", "  12:00-13:00 ● Lunch", "  15:00-16:00 ● Retro"},
+		},
+		{
+			name: "after the last event",
+			now:  testTime("17:00"),
+			want: []string{"  09:00-09:15 ● Standup", "  09:45-10:30 ● Review", "  12:00-13:00 ● Lunch", "  15:00-16:00 ● Retro", divider("17:00")},
+		},
+		{
+			name: "another day",
+			now:  testTime("10:00").AddDate(0, 0, 1),
+			want: []string{"  09:00-09:15 ● Standup", "  09:45-10:30 ● Review", "  12:00-13:00 ● Lunch", "  15:00-16:00 ● Retro"},
+		},
+		{
+			name:    "grouped",
+			now:     testTime("11:00"),
+			groupBy: groupByTimeOfDay,
+			want: []string{"Morning", "  09:00-09:15 ● Standup", "  09:45-10:30 ● Review", "", "Afternoon",
+				divider("11:00"), "  12:00-13:00 ● Lunch", "  15:00-16:00 ● Retro"},
+		},
+		{
+			// Morning events that have not ended are shown before the afternoon
+			name:    "grouped after the last group started",
+			now:     testTime("14:00"),
+			groupBy: groupByTimeOfDay,
+			want: []string{"Morning", "  09:00-09:15 ● Standup", "  09:45-10:30 ● Review", "", "Afternoon",
+				"  12:00-13:00 ● Lunch", divider("14:00"), "  15:00-16:00 ● Retro"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderPretty(t, tt.now, agenda, tt.groupBy)
+			if want := strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestPrettyRendererDividerBetweenDays(t *testing.T) {
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events := []models.CalendarEvent{
+		testEvent("Standup", "09:00", "09:15"),
+		{Title: "Planning", StartTime: testTime("09:00").AddDate(0, 0, 1), EndTime: testTime("10:00").AddDate(0, 0, 1)},
+		{Title: "Retro", StartTime: testTime("09:00").AddDate(0, 0, 2), EndTime: testTime("10:00").AddDate(0, 0, 2)},
+	}
+	agenda := splitByDay(events, make([][]string, len(events)), start, start.AddDate(0, 0, 3))
+
+	// Only today has a divider, even if all events of the previous days are over
+	got := renderPretty(t, testTime("12:00").AddDate(0, 0, 1), agenda, groupByNone)
+	want := strings.Join([]string{
+		"Mon 2025-03-10", "  09:00-09:15 ● Standup",
+		"Tue 2025-03-11", "  09:00-10:00 ● Planning", divider("12:00"),
+		"Wed 2025-03-12", "  09:00-10:00 ● Retro",
+	}, "\n")
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrettyRendererConflicts(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	standup := testEvent("Standup", "09:00", "09:30")
+	standup.CalendarName = "Work"
+	events := []models.CalendarEvent{standup, testEvent("Review", "09:15", "10:00")}
+	agenda := splitByDay(events, [][]string{{"Review"}, {"Standup"}}, day, day.AddDate(0, 0, 1))
+
+	got := renderPretty(t, testTime("08:00"), agenda, groupByNone)
+	want := divider("08:00") + "\n" +
+		"  09:00-09:30 ● Standup (Work) ⚠ overlaps Review\n" +
+		"  09:15-10:00 ● Review ⚠ overlaps Standup"
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		color   string
+		r, g, b uint8
+		ok      bool
+	}{
+		{"#3b82f6", 0x3b, 0x82, 0xf6, true},
+		{"3b82f6", 0x3b, 0x82, 0xf6, true},
+		{"#fa0", 0xff, 0xaa, 0x00, true},
+		{"", 0, 0, 0, false},
+		{"#12345", 0, 0, 0, false},
+		{"#zzzzzz", 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		r, g, b, ok := parseHexColor(tt.color)
+		if r != tt.r || g != tt.g || b != tt.b || ok != tt.ok {
+			t.Errorf("parseHexColor(%q) = %d, %d, %d, %t, want %d, %d, %d, %t", tt.color, r, g, b, ok, tt.r, tt.g, tt.b, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	configs "github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// agendaDay holds the events of a single day of the agenda.
+type agendaDay struct {
+	Date time.Time
+	// Count is the number of events on the day.
+	Count  int
+	events []models.CalendarEvent
+	// overlapping holds the titles of the conflicting events for each event.
+	overlapping [][]string
+}
+
+// agendaRenderer prints the agenda of a day.
+type agendaRenderer interface {
+	Render(day agendaDay, groups []eventGroup, showDayHeading, showGroupHeadings bool)
+}
+
+// splitByDay splits the sorted events in [start, end) into days. Days without events are skipped.
+func splitByDay(events []models.CalendarEvent, overlapping [][]string, start, end time.Time) []agendaDay {
+	var days []agendaDay
+	first := 0
+	for date := start; date.Before(end) && first < len(events); date = date.AddDate(0, 0, 1) {
+		nextDate := date.AddDate(0, 0, 1)
+		last := first
+		for last < len(events) && events[last].StartTime.Before(nextDate) {
+			last++
+		}
+		if last > first {
+			days = append(days, agendaDay{
+				Date:        date,
+				Count:       last - first,
+				events:      events[first:last],
+				overlapping: overlapping[first:last],
+			})
+		}
+		first = last
+	}
+	return days
+}
+
+// markdownRenderer prints the agenda using the configured templates.
+type markdownRenderer struct {
+	out       io.Writer
+	formatter *EventFormatter
+	config    configs.Config
+	// showIDs appends the ID of each event.
+	showIDs bool
+}
+
+// Render prints the grouped events of the day.
+func (r *markdownRenderer) Render(day agendaDay, groups []eventGroup, showDayHeading, showGroupHeadings bool) {
+	if showDayHeading {
+		heading, err := r.formatter.FormatDayHeading(r.config.DayHeadingTemplate, day)
+		if err != nil {
+			log.Fatalf("Failed to format day heading: %v", err)
+		}
+		fmt.Fprintln(r.out, heading)
+	}
+
+	for i, group := range groups {
+		if showGroupHeadings {
+			heading, err := r.formatter.FormatGroupHeading(r.config.GroupHeadingTemplate, group)
+			if err != nil {
+				log.Fatalf("Failed to format group heading: %v", err)
+			}
+			if i > 0 {
+				fmt.Fprintln(r.out)
+			}
+			fmt.Fprintln(r.out, heading)
+		}
+
+		for _, index := range group.indices {
+			event := day.events[index]
+			formatted, err := r.formatter.FormatEvent(event, day.overlapping[index])
+			if err != nil {
+				log.Printf("Warning: failed to format event %s: %v", event.Title, err)
+				continue
+			}
+			if r.showIDs && event.ID != "" {
+				formatted += fmt.Sprintf(" [%s]", event.ID)
+			}
+			fmt.Fprintln(r.out, formatted)
+		}
+	}
+}
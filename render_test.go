@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+// formatDays formats the days as "03-10: title, title; 03-12: title".
+func formatDays(days []agendaDay) string {
+	var formatted []string
+	for _, day := range days {
+		var titles []string
+		for i, event := range day.events {
+			titles = append(titles, event.Title+strings.Join(day.overlapping[i], ""))
+		}
+		if len(titles) != day.Count {
+			titles = append(titles, "count mismatch")
+		}
+		formatted = append(formatted, fmt.Sprintf("%s: %s", day.Date.Format("01-02"), strings.Join(titles, ", ")))
+	}
+	return strings.Join(formatted, "; ")
+}
+
+func TestSplitByDay(t *testing.T) {
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	at := func(days int, clock string) time.Time {
+		return testTime(clock).AddDate(0, 0, days)
+	}
+	event := func(title string, days int, startClock string, endDays int, endClock string) models.CalendarEvent {
+		return models.CalendarEvent{Title: title, StartTime: at(days, startClock), EndTime: at(endDays, endClock)}
+	}
+
+	tests := []struct {
+		name   string
+		events []models.CalendarEvent
+		end    time.Time
+		want   string
+	}{
+		{
+			name:   "single day",
+			events: []models.CalendarEvent{event("Standup", 0, "09:00", 0, "09:15"), event("Review", 0, "14:00", 0, "15:00")},
+			end:    start.AddDate(0, 0, 1),
+			want:   "03-10: Standup, Review",
+		},
+		{
+			name: "days without events are skipped",
+			events: []models.CalendarEvent{
+				event("Standup", 0, "09:00", 0, "09:15"),
+				event("Planning", 2, "10:00", 2, "11:00"),
+			},
+			end:  start.AddDate(0, 0, 7),
+			want: "03-10: Standup; 03-12: Planning",
+		},
+		{
+			name: "events crossing midnight belong to the day they start on",
+			events: []models.CalendarEvent{
+				event("Night shift", 0, "22:00", 1, "06:00"),
+				event("Breakfast", 1, "07:00", 1, "08:00"),
+			},
+			end:  start.AddDate(0, 0, 2),
+			want: "03-10: Night shift; 03-11: Breakfast",
+		},
+		{
+			name: "events started before the range belong to the first day",
+			events: []models.CalendarEvent{
+				event("Release", -1, "23:00", 0, "01:00"),
+				event("Standup", 0, "09:00", 0, "09:15"),
+			},
+			end:  start.AddDate(0, 0, 1),
+			want: "03-10: Release, Standup",
+		},
+		{
+			name:   "no events",
+			events: nil,
+			end:    start.AddDate(0, 0, 7),
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlapping := make([][]string, len(tt.events))
+			if got := formatDays(splitByDay(tt.events, overlapping, start, tt.end)); got != tt.want {
+				t.Errorf("splitByDay() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitByDayKeepsOverlaps(t *testing.T) {
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	events := []models.CalendarEvent{
+		testEvent("Standup", "09:00", "09:30"),
+		{Title: "Review", StartTime: testTime("09:00").AddDate(0, 0, 1), EndTime: testTime("10:00").AddDate(0, 0, 1)},
+		{Title: "Interview", StartTime: testTime("09:30").AddDate(0, 0, 1), EndTime: testTime("10:30").AddDate(0, 0, 1)},
+	}
+	overlapping := [][]string{nil, {"(Interview)"}, {"(Review)"}}
+
+	got := formatDays(splitByDay(events, overlapping, start, start.AddDate(0, 0, 2)))
+	if want := "03-10: Standup; 03-11: Review(Interview), Interview(Review)"; got != want {
+		t.Errorf("splitByDay() = %s, want %s", got, want)
+	}
+}
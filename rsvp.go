@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	configs "github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+	providers "github.com/DeveloperPaul123/agenda/internal/providers"
+)
+
+// rsvpResponses maps the responses accepted on the command line to the invitation responses.
+var rsvpResponses = map[string]string{
+	"accept":    models.ResponseAccepted,
+	"decline":   models.ResponseDeclined,
+	"tentative": models.ResponseTentative,
+}
+
+// rsvpPrompts name the responses in confirmation prompts.
+var rsvpPrompts = map[string]string{
+	models.ResponseAccepted:  "Accept",
+	models.ResponseDeclined:  "Decline",
+	models.ResponseTentative: "Tentatively accept",
+}
+
+// rsvpVerbs describe the invitation responses in messages.
+var rsvpVerbs = map[string]string{
+	models.ResponseAccepted:  "Accepted",
+	models.ResponseDeclined:  "Declined",
+	models.ResponseTentative: "Tentatively accepted",
+}
+
+// newRSVPCommand creates the command that answers invitations.
+func newRSVPCommand() *cobra.Command {
+	rsvpCmd := &cobra.Command{
+		Use:   "rsvp [EVENT] accept|decline|tentative",
+		Short: "Answer an invitation, or with --all every invitation of the day, e.g. agenda rsvp \"Review\" accept",
+		Args:  validateRSVPArgs,
+		Run:   runRSVP,
+	}
+	rsvpCmd.Flags().String("comment", "", "Message sent to the organizer with the response")
+	rsvpCmd.Flags().Bool("all", false, "Answer all invitations on the day given with --date, e.g. to decline them during a vacation")
+	rsvpCmd.Flags().String("match", "", "Only answer the invitations whose title contains this text, used with --all")
+	addChangeFlags(rsvpCmd)
+	return rsvpCmd
+}
+
+// validateRSVPArgs checks that an event is named unless --all is given, and that the response is known.
+func validateRSVPArgs(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	want := 2
+	if all {
+		want = 1
+	}
+	if len(args) != want {
+		return fmt.Errorf("expected %d arguments, got %d", want, len(args))
+	}
+	if _, exists := rsvpResponses[args[len(args)-1]]; !exists {
+		return fmt.Errorf("invalid response %q, expected accept, decline or tentative", args[len(args)-1])
+	}
+	return nil
+}
+
+// rsvpTarget is an invitation and the provider that answers it.
+type rsvpTarget struct {
+	provider providers.RSVPProvider
+	event    models.CalendarEvent
+}
+
+// runRSVP answers the invitation to an event, or all invitations of the day with --all.
+func runRSVP(cmd *cobra.Command, args []string) {
+	config := loadConfig(cmd)
+	comment, _ := cmd.Flags().GetString("comment")
+	all, _ := cmd.Flags().GetBool("all")
+	match, _ := cmd.Flags().GetString("match")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	response := rsvpResponses[args[len(args)-1]]
+
+	start, end := parseDateFlag(cmd, "date", currentTime(config))
+	targets := fetchInvitations(cmd, config, start, end)
+	formatter := newFormatter(config)
+
+	if !all {
+		target := pickInvitation(config, targets, args[0])
+		event := target.event
+		if !confirmChange(cmd, fmt.Sprintf("%s %s?", rsvpPrompts[response], describeEvent(formatter, event))) {
+			return
+		}
+		err := target.provider.RespondToEvent(event, response, comment)
+		if reportChange(err, "answer") {
+			fmt.Printf("%s %s\n", rsvpVerbs[response], event.Title)
+		}
+		return
+	}
+
+	selected := selectInvitations(targets, response, match)
+	if len(selected) == 0 {
+		fmt.Println("No invitations to answer found.")
+		return
+	}
+
+	for _, target := range selected {
+		fmt.Printf("- %s %s\n", formatter.FormatTimeRange(target.event), describeEvent(formatter, target.event))
+	}
+	if !confirmChange(cmd, fmt.Sprintf("%s these %d invitations?", rsvpPrompts[response], len(selected))) {
+		return
+	}
+
+	failed := 0
+	for _, target := range selected {
+		err := target.provider.RespondToEvent(target.event, response, comment)
+		switch {
+		case errors.Is(err, providers.ErrDryRun):
+		case err != nil:
+			log.Printf("Failed to answer %s: %v", target.event.Title, err)
+			failed++
+		default:
+			fmt.Printf("%s %s\n", rsvpVerbs[response], target.event.Title)
+		}
+	}
+	if dryRun {
+		fmt.Println("Dry run, no invitation was answered.")
+	}
+	if failed > 0 {
+		log.Fatalf("Failed to answer %d of %d invitations", failed, len(selected))
+	}
+}
+
+// pickInvitation returns the invitation that the target names, see pickEvent. It exits if the event
+// is not an invitation, e.g. because the user organizes it.
+func pickInvitation(config configs.Config, targets []rsvpTarget, target string) rsvpTarget {
+	events := make([]models.CalendarEvent, len(targets))
+	for i, invitation := range targets {
+		events[i] = invitation.event
+	}
+	invitation := targets[pickEvent(config, events, target)]
+	if invitation.event.ResponseStatus == "" {
+		log.Fatalf("You organize %s or it has no attendees, there is no invitation to answer", invitation.event.Title)
+	}
+	return invitation
+}
+
+// selectInvitations returns the invitations answered by --all: the open ones and the ones answered
+// differently before, whose title contains match if it is set. Events without a response, e.g. the
+// ones the user organizes, are not invitations.
+func selectInvitations(targets []rsvpTarget, response, match string) []rsvpTarget {
+	var selected []rsvpTarget
+	for _, target := range targets {
+		event := target.event
+		if event.ResponseStatus == "" || event.ResponseStatus == response {
+			continue
+		}
+		if match != "" && !strings.Contains(strings.ToLower(event.Title), strings.ToLower(match)) {
+			continue
+		}
+		selected = append(selected, target)
+	}
+	return selected
+}
+
+// fetchInvitations retrieves the events in [start, end) from the configured provider and the
+// providers merged with it that can answer invitations.
+func fetchInvitations(cmd *cobra.Command, config configs.Config, start, end time.Time) []rsvpTarget {
+	names, calProviders := createProviders(cmd, config)
+	var targets []rsvpTarget
+	supported := false
+	for i, calProvider := range calProviders {
+		rsvpProvider, ok := calProvider.(providers.RSVPProvider)
+		if !ok {
+			continue
+		}
+		supported = true
+
+		events, err := calProvider.GetEvents(start, end)
+		if err != nil {
+			log.Fatalf("Failed to get events from %s: %v", names[i], err)
+		}
+		for _, event := range events {
+			event.StartTime = event.StartTime.In(start.Location())
+			event.EndTime = event.EndTime.In(start.Location())
+			event.Provider = names[i]
+			targets = append(targets, rsvpTarget{provider: rsvpProvider, event: event})
+		}
+	}
+	if !supported {
+		log.Fatalf("None of the providers %s can answer invitations", strings.Join(names, ", "))
+	}
+	return targets
+}
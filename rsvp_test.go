@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	configs "github.com/DeveloperPaul123/agenda/internal/configs"
+	models "github.com/DeveloperPaul123/agenda/internal/models"
+)
+
+func TestSelectInvitations(t *testing.T) {
+	invitation := func(title, response string) rsvpTarget {
+		event := testEvent(title, "09:00", "10:00")
+		event.ResponseStatus = response
+		return rsvpTarget{event: event}
+	}
+	targets := []rsvpTarget{
+		invitation("Standup", models.ResponseAccepted),
+		invitation("Design review", models.ResponseNeedsAction),
+		invitation("Code review", models.ResponseTentative),
+		invitation("Offsite", models.ResponseDeclined),
+		// Events the user organizes or that are not invitations have no response
+		invitation("Review prep", ""),
+		invitation("Focus", ""),
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		match    string
+		want     []string
+	}{
+		{"decline all", models.ResponseDeclined, "", []string{"Standup", "Design review", "Code review"}},
+		{"accept all", models.ResponseAccepted, "", []string{"Design review", "Code review", "Offsite"}},
+		{"tentative all", models.ResponseTentative, "", []string{"Standup", "Design review", "Offsite"}},
+		{"match", models.ResponseDeclined, "REVIEW", []string{"Design review", "Code review"}},
+		{"match without invitations", models.ResponseAccepted, "focus", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, target := range selectInvitations(targets, tt.response, tt.match) {
+				got = append(got, target.event.Title)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("selectInvitations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// namedRSVPProvider is an RSVP provider that only has a name to tell it apart.
+type namedRSVPProvider struct {
+	name string
+}
+
+func (p *namedRSVPProvider) RespondToEvent(models.CalendarEvent, string, string) error {
+	return nil
+}
+
+func TestPickInvitation(t *testing.T) {
+	// Both providers return an invitation with the same ID
+	standup := testEvent("Standup", "09:00", "09:15")
+	standup.ResponseStatus = models.ResponseNeedsAction
+	review := testEvent("Review", "11:00", "12:00")
+	review.ID = standup.ID
+	review.ResponseStatus = models.ResponseAccepted
+	targets := []rsvpTarget{
+		{provider: &namedRSVPProvider{name: "work"}, event: standup},
+		{provider: &namedRSVPProvider{name: "personal"}, event: review},
+	}
+
+	invitation := pickInvitation(configs.DefaultConfig(), targets, "Standup")
+	if provider := invitation.provider.(*namedRSVPProvider); provider.name != "work" || invitation.event.Title != "Standup" {
+		t.Errorf("pickInvitation() = %s of %s, want Standup of work", invitation.event.Title, provider.name)
+	}
+}
+
+func TestPickInvitationOrganized(t *testing.T) {
+	// pickInvitation exits if the event is not an invitation, so it runs in a child process
+	if os.Getenv("AGENDA_TEST_PICK_INVITATION") != "" {
+		pickInvitation(configs.DefaultConfig(), []rsvpTarget{{event: testEvent("Planning", "09:00", "10:00")}}, "planning")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestPickInvitationOrganized$")
+	cmd.Env = append(os.Environ(), "AGENDA_TEST_PICK_INVITATION=1")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("pickInvitation() did not exit for an event without a response")
+	}
+	if !strings.Contains(string(output), "You organize Planning") {
+		t.Errorf("pickInvitation() output = %s", output)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	configs "github.com/DeveloperPaul123/agenda/internal/configs"
+)
+
+// newStatsCommand creates the command that prints meeting statistics for a day or a range of days.
+func newStatsCommand() *cobra.Command {
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Print meeting statistics for a day or a range of days",
+		Run:   runStats,
+	}
+	statsCmd.Flags().String("to", "", "Last date of the range to get statistics for, accepts the same formats as --date (default is --date)")
+	statsCmd.Flags().String("template", "", "Override the stats template from config")
+	return statsCmd
+}
+
+// runStats prints the meeting statistics using the stats template.
+func runStats(cmd *cobra.Command, args []string) {
+	config := loadConfig(cmd)
+	statsTemplate, _ := cmd.Flags().GetString("template")
+
+	if statsTemplate != "" {
+		config.StatsTemplate = statsTemplate
+	}
+	if config.StatsTemplate == "" {
+		config.StatsTemplate = configs.DEFAULT_STATS_TEMPLATE
+	}
+
+	now := currentTime(config)
+	start, end := parseDateFlag(cmd, "date", now)
+	if cmd.Flags().Changed("to") {
+		_, end = parseDateFlag(cmd, "to", now)
+	}
+	if !end.After(start) {
+		log.Fatalf("Invalid range: --to is before --date")
+	}
+
+	events := fetchEvents(cmd, config, start, end)
+
+	formatter := newFormatter(config)
+	formatted, err := formatter.FormatSummary(config.StatsTemplate, summarize(config, events, start, end))
+	if err != nil {
+		log.Fatalf("Failed to format stats: %v", err)
+	}
+	fmt.Println(formatted)
+}